@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package bdaddr provides tools for parsing and classifying Bluetooth device addresses.
+package bdaddr
+
+import (
+	"crypto/aes"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+const AddressRegEx = "^(([0-9A-Fa-f]{2}:){5}|([0-9A-Fa-f]{2}-){5}|([0-9A-Fa-f]{2}){5})[0-9A-Fa-f]{2}$"
+const IrkRegEx = "^(0x)?[0-9A-Fa-f]{32}$"
+
+// Address is a 48-bit Bluetooth device address (BD_ADDR) stored in the order it is displayed, most significant byte first.
+type Address [6]byte
+
+// AddressType describes the kind of Bluetooth device address.
+type AddressType int
+
+const (
+	// Public is an IEEE assigned public device address.
+	Public AddressType = iota
+	// RandomStatic is a random static device address (top two bits 0b11).
+	RandomStatic
+	// ResolvablePrivate is a resolvable private address (top two bits 0b01).
+	ResolvablePrivate
+	// NonResolvablePrivate is a non-resolvable private address (top two bits 0b00).
+	NonResolvablePrivate
+	// Reserved is a random address with top two bits 0b10 which is reserved for future use.
+	Reserved
+)
+
+// IRK is a 128-bit Identity Resolving Key stored most significant byte first.
+type IRK [16]byte
+
+// ParseAddress parses Bluetooth device address in "00:1A:7D:DA:71:13", "00-1A-7D-DA-71-13" or "001A7DDA7113" form. If incorrectly formed address is given as input an error is returned.
+func ParseAddress(name string) (Address, error) {
+	if match, _ := regexp.MatchString(AddressRegEx, name); !match {
+		return Address{}, errors.New("invalid input (address)")
+	}
+
+	name = strings.NewReplacer(":", "", "-", "").Replace(name)
+
+	var out Address
+	if _, err := hex.Decode(out[:], []byte(name)); err != nil {
+		return Address{}, errors.New("invalid input (address)")
+	}
+
+	return out, nil
+}
+
+// String returns address in canonical upper case colon separated form.
+func (a Address) String() string {
+	var parts [6]string
+	for i, b := range a {
+		parts[i] = strings.ToUpper(hex.EncodeToString([]byte{b}))
+	}
+
+	return strings.Join(parts[:], ":")
+}
+
+// Classify determines address type. Whether address is public or random is not encoded in the address itself, it is carried by the TxAdd/RxAdd bit of the advertising PDU and needs to be provided by the caller. Random addresses are classified by their two most significant bits.
+func Classify(addr Address, random bool) AddressType {
+	if !random {
+		return Public
+	}
+
+	switch addr[0] >> 6 {
+	case 0b11:
+		return RandomStatic
+	case 0b01:
+		return ResolvablePrivate
+	case 0b00:
+		return NonResolvablePrivate
+	default:
+		return Reserved
+	}
+}
+
+// String returns human readable name of the address type.
+func (t AddressType) String() string {
+	switch t {
+	case Public:
+		return "public"
+	case RandomStatic:
+		return "random static"
+	case ResolvablePrivate:
+		return "resolvable private"
+	case NonResolvablePrivate:
+		return "non-resolvable private"
+	case Reserved:
+		return "reserved"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseIRK parses Identity Resolving Key given as 32 hex digits, most significant byte first, with optional "0x" prefix.
+func ParseIRK(name string) (IRK, error) {
+	if match, _ := regexp.MatchString(IrkRegEx, name); !match {
+		return IRK{}, errors.New("invalid input (IRK)")
+	}
+
+	var out IRK
+	if _, err := hex.Decode(out[:], []byte(strings.TrimPrefix(name, "0x"))); err != nil {
+		return IRK{}, errors.New("invalid input (IRK)")
+	}
+
+	return out, nil
+}
+
+// Ah implements the random address hash function "ah" from Bluetooth Core Specification Vol 3, Part H, 2.2.2.
+func Ah(irk IRK, prand [3]byte) [3]byte {
+	// r' = padding || r, where padding is 13 zero octets
+	var plaintext [16]byte
+	copy(plaintext[13:], prand[:])
+
+	// aes.NewCipher can only fail on invalid key length and IRK is always 16 bytes
+	block, _ := aes.NewCipher(irk[:])
+
+	var ciphertext [16]byte
+	block.Encrypt(ciphertext[:], plaintext[:])
+
+	// ah(k, r) = e(k, r') mod 2^24
+	var out [3]byte
+	copy(out[:], ciphertext[13:])
+
+	return out
+}
+
+// Resolve tries to resolve resolvable private address against given IRKs. Index of the matching IRK is returned, or false if address is not resolvable private address or none of the keys match.
+func Resolve(addr Address, irks []IRK) (int, bool) {
+	if Classify(addr, true) != ResolvablePrivate {
+		return -1, false
+	}
+
+	var prand, hash [3]byte
+	copy(prand[:], addr[:3])
+	copy(hash[:], addr[3:])
+
+	for i, irk := range irks {
+		if Ah(irk, prand) == hash {
+			return i, true
+		}
+	}
+
+	return -1, false
+}
+
+// Eui64 converts the address into EUI-64 hex string by inserting "fffe" between OUI and extension identifier.
+func (a Address) Eui64() string {
+	return hex.EncodeToString(a[:3]) + "fffe" + hex.EncodeToString(a[3:])
+}
+
+// ToUrnDev maps public address into urn:dev:mac identifier. Random addresses are not based on EUI-48 and an error is returned for them.
+func ToUrnDev(addr Address, random bool) (rfc9039.UrnDev, error) {
+	if Classify(addr, random) != Public {
+		return rfc9039.UrnDev{}, errors.New("invalid input (not public address)")
+	}
+
+	return rfc9039.Parse(rfc9039.UrnDevPrefix + "mac:" + addr.Eui64())
+}
@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package bdaddr
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func ExampleParseAddress() {
+	addr, _ := ParseAddress("00-1a-7d-da-71-13")
+	fmt.Println(addr)
+	fmt.Println(Classify(addr, false))
+	// Output: 00:1A:7D:DA:71:13
+	// public
+}
+
+func ExampleToUrnDev() {
+	addr, _ := ParseAddress("00:24:BE:80:4F:F1")
+	devUrn, _ := ToUrnDev(addr, false)
+	fmt.Println(devUrn.FullName)
+	// Output: urn:dev:mac:0024befffe804ff1
+}
+
+func TestParseAddress(t *testing.T) {
+	expected := Address{0x00, 0x1a, 0x7d, 0xda, 0x71, 0x13}
+
+	for _, input := range []string{"00:1A:7D:DA:71:13", "00-1a-7d-da-71-13", "001A7DDA7113"} {
+		value, err := ParseAddress(input)
+		if err != nil {
+			t.Fatalf("Failed to parse %s", input)
+			return
+		}
+		assert.Equal(t, expected, value)
+		assert.Equal(t, "00:1A:7D:DA:71:13", value.String())
+	}
+}
+
+func TestParseAddressInvalid(t *testing.T) {
+	for _, input := range []string{"", "00:1A:7D:DA:71", "00:1A:7D:DA:71:13:00", "00:1A-7D:DA:71:13", "00:1A:7D:DA:71:1G", "001A7DDA711"} {
+		value, err := ParseAddress(input)
+		if err == nil {
+			t.Fatalf("Failed to reject %s", input)
+			return
+		}
+		assert.Equal(t, Address{}, value)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	assert.Equal(t, Public, Classify(Address{0xc0, 0x00, 0x00, 0x00, 0x00, 0x00}, false))
+	assert.Equal(t, RandomStatic, Classify(Address{0xc0, 0x00, 0x00, 0x00, 0x00, 0x00}, true))
+	assert.Equal(t, ResolvablePrivate, Classify(Address{0x70, 0x81, 0x94, 0x0d, 0xfb, 0xaa}, true))
+	assert.Equal(t, NonResolvablePrivate, Classify(Address{0x3f, 0xff, 0xff, 0xff, 0xff, 0xff}, true))
+	assert.Equal(t, Reserved, Classify(Address{0x80, 0x00, 0x00, 0x00, 0x00, 0x00}, true))
+}
+
+func TestAddressTypeString(t *testing.T) {
+	assert.Equal(t, "public", Public.String())
+	assert.Equal(t, "random static", RandomStatic.String())
+	assert.Equal(t, "resolvable private", ResolvablePrivate.String())
+	assert.Equal(t, "non-resolvable private", NonResolvablePrivate.String())
+	assert.Equal(t, "reserved", Reserved.String())
+	assert.Equal(t, "unknown", AddressType(42).String())
+}
+
+func TestParseIRK(t *testing.T) {
+	value, err := ParseIRK("0xec0234a357c8ad05341010a60a397d9b")
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, IRK{0xec, 0x02, 0x34, 0xa3, 0x57, 0xc8, 0xad, 0x05, 0x34, 0x10, 0x10, 0xa6, 0x0a, 0x39, 0x7d, 0x9b}, value)
+
+	_, err = ParseIRK("ec0234a357c8ad05341010a60a397d9")
+	assert.Error(t, err)
+}
+
+func TestAh(t *testing.T) {
+	// Bluetooth Core Specification Vol 3, Part H, D.7 sample data
+	irk, _ := ParseIRK("ec0234a357c8ad05341010a60a397d9b")
+	assert.Equal(t, [3]byte{0x0d, 0xfb, 0xaa}, Ah(irk, [3]byte{0x70, 0x81, 0x94}))
+}
+
+func TestResolve(t *testing.T) {
+	other, _ := ParseIRK("00112233445566778899aabbccddeeff")
+	irk, _ := ParseIRK("ec0234a357c8ad05341010a60a397d9b")
+	addr, _ := ParseAddress("70:81:94:0D:FB:AA")
+
+	index, ok := Resolve(addr, []IRK{other, irk})
+	assert.True(t, ok)
+	assert.Equal(t, 1, index)
+
+	index, ok = Resolve(addr, []IRK{other})
+	assert.False(t, ok)
+	assert.Equal(t, -1, index)
+}
+
+func TestResolveNotResolvable(t *testing.T) {
+	irk, _ := ParseIRK("ec0234a357c8ad05341010a60a397d9b")
+	addr, _ := ParseAddress("F0:81:94:0D:FB:AA")
+
+	_, ok := Resolve(addr, []IRK{irk})
+	assert.False(t, ok)
+}
+
+func TestToUrnDev(t *testing.T) {
+	addr, _ := ParseAddress("00:24:BE:80:4F:F1")
+
+	value, err := ToUrnDev(addr, false)
+	if err != nil {
+		t.Fatalf("Failed to convert")
+		return
+	}
+	assert.Equal(t, "urn:dev:mac:0024befffe804ff1", value.FullName)
+	assert.Equal(t, "mac", value.Subtype)
+	assert.Equal(t, "0024befffe804ff1", value.Eui64Identifier)
+}
+
+func TestToUrnDevRandom(t *testing.T) {
+	addr, _ := ParseAddress("C0:24:BE:80:4F:F1")
+
+	_, err := ToUrnDev(addr, true)
+	assert.Error(t, err)
+}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package byid provides tools for parsing Linux udev persistent device names under /dev/disk/by-id and /dev/serial/by-id into urn:dev identifiers.
+package byid
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+	"github.com/RisingEdgeSolutions/device-identifiers/storage"
+)
+
+// NameRegEx splits name into bus, device part and suffixes added by udev rules: LUN of USB storage, USB interface number, serial port number and partition number.
+const NameRegEx = "^([a-z][a-z0-9]*)-(.+?)(-[0-9]+:[0-9]+)?(-if[0-9A-Fa-f]{2})?(-port[0-9]+)?(-part[0-9]+)?$"
+
+// Subtype is the urn:dev otherbody subtype of names which do not carry World Wide Name.
+const Subtype = "byid"
+
+// Name captures parsed by-id name.
+type Name struct {
+	// Bus is the bus prefix of the name, e.g. "usb", "ata", "nvme", "scsi" or "wwn".
+	Bus string
+	// Model is the model part of udev ID_SERIAL with spaces replaced by "_". With USB it is vendor and model, which can not be told apart.
+	Model string
+	// Serial is the last "_" separated part of udev ID_SERIAL.
+	Serial string
+	// WWN is set for "wwn-", "nvme-eui." and "scsi-" NAA and EUI-64 names, when HasWWN is set.
+	WWN storage.Identifier
+	// HasWWN tells whether WWN is set.
+	HasWWN bool
+	// LUN is the SCSI logical unit of USB storage, e.g. "0:0".
+	LUN string
+	// Interface is the USB interface number, e.g. "00".
+	Interface string
+	// Port is the serial port number of multi-port adapters, e.g. "0".
+	Port string
+	// Partition is the partition number, e.g. "1".
+	Partition string
+}
+
+// Parse parses by-id name such as "usb-FTDI_FT232R_USB_UART_A50285BI-if00-port0", "ata-Samsung_SSD_860_EVO_500GB_S3Z9NB0K123456-part1", "nvme-eui.0025385b71b07e2f" or "wwn-0x5000c500a1b2c3d4". Directory part of path, e.g. "/dev/serial/by-id/", is ignored. If incorrectly formed name is given as input an error is returned.
+func Parse(name string) (Name, error) {
+	match := regexp.MustCompile(NameRegEx).FindStringSubmatch(path.Base(name))
+	if match == nil {
+		return Name{}, errors.New("invalid input (name)")
+	}
+
+	out := Name{
+		Bus:       match[1],
+		LUN:       strings.TrimPrefix(match[3], "-"),
+		Interface: strings.TrimPrefix(match[4], "-if"),
+		Port:      strings.TrimPrefix(match[5], "-port"),
+		Partition: strings.TrimPrefix(match[6], "-part"),
+	}
+	body := match[2]
+
+	// scsi_id prefixes the designator with its type, 3 for NAA, 2 for EUI-64, 1 for T10 vendor ID and S for vendor, model and unit serial number
+	wwn := ""
+	switch {
+	case out.Bus == "wwn":
+		wwn = body
+	case out.Bus == "nvme" && strings.HasPrefix(body, "eui."):
+		wwn = body
+	case out.Bus == "scsi" && strings.HasPrefix(body, "3"):
+		wwn = "naa." + body[1:]
+	case out.Bus == "scsi" && strings.HasPrefix(body, "2"):
+		wwn = "eui." + body[1:]
+	}
+
+	if wwn != "" {
+		id, err := storage.ParseName(wwn)
+		if err != nil {
+			return Name{}, err
+		}
+		out.WWN = id
+		out.HasWWN = true
+
+		return out, nil
+	}
+
+	if out.Bus == "scsi" && (strings.HasPrefix(body, "1") || strings.HasPrefix(body, "S")) {
+		body = body[1:]
+	}
+
+	if index := strings.LastIndex(body, "_"); index >= 0 {
+		out.Model = body[:index]
+		out.Serial = body[index+1:]
+	} else {
+		out.Serial = body
+	}
+
+	if out.Serial == "" {
+		return Name{}, errors.New("invalid input (serial)")
+	}
+
+	return out, nil
+}
+
+// Components returns urn:dev components of the name: "lun" followed by LUN with ":" replaced by ".", "if" followed by interface number, "port" followed by port number and "part" followed by partition number, in this order.
+func (n Name) Components() []string {
+	out := []string{}
+
+	if n.LUN != "" {
+		out = append(out, "lun"+strings.ReplaceAll(n.LUN, ":", "."))
+	}
+
+	if n.Interface != "" {
+		out = append(out, "if"+strings.ToLower(n.Interface))
+	}
+
+	if n.Port != "" {
+		out = append(out, "port"+n.Port)
+	}
+
+	if n.Partition != "" {
+		out = append(out, "part"+n.Partition)
+	}
+
+	return out
+}
+
+// identifier converts value into urn:dev identifier by replacing runs of characters not valid in urn:dev, including "_", with ".".
+func identifier(value string) string {
+	return strings.Trim(regexp.MustCompile("[^A-Za-z0-9\\.\\-]+").ReplaceAllString(value, "."), ".")
+}
+
+// ToUrnDev maps name into urn:dev identifier with Components as component part. Names with World Wide Name are mapped with storage.Identifier.ToUrnDev using penByOUI and other names into otherbody identifier with bus, model and serial, for example "urn:dev:byid:usb:FTDI.FT232R.USB.UART:A50285BI_if00_port0".
+func (n Name) ToUrnDev(penByOUI map[string]int) (rfc9039.UrnDev, error) {
+	name := ""
+
+	if n.HasWWN {
+		devUrn, err := n.WWN.ToUrnDev(penByOUI)
+		if err != nil {
+			return rfc9039.UrnDev{}, err
+		}
+		name = devUrn.FullName
+	} else {
+		name = rfc9039.UrnDevPrefix + Subtype + ":" + n.Bus
+		if model := identifier(n.Model); model != "" {
+			name += ":" + model
+		}
+		name += ":" + identifier(n.Serial)
+	}
+
+	for _, component := range n.Components() {
+		name += "_" + component
+	}
+
+	return rfc9039.Parse(name)
+}
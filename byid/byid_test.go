@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package byid
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func ExampleParse() {
+	name, _ := Parse("/dev/serial/by-id/usb-FTDI_FT232R_USB_UART_A50285BI-if00-port0")
+	fmt.Println(name.Bus, name.Model, name.Serial, name.Interface, name.Port)
+
+	devUrn, _ := name.ToUrnDev(nil)
+	fmt.Println(devUrn.FullName)
+	// Output: usb FTDI_FT232R_USB_UART A50285BI 00 0
+	// urn:dev:byid:usb:FTDI.FT232R.USB.UART:A50285BI_if00_port0
+}
+
+func TestParse(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		urn  string
+	}{
+		{"usb-Silicon_Labs_CP2102_USB_to_UART_Bridge_Controller_0001-if00-port0", "urn:dev:byid:usb:Silicon.Labs.CP2102.USB.to.UART.Bridge.Controller:0001_if00_port0"},
+		{"usb-FTDI_Quad_RS232-HS_FT4ABCDE-if03-port0", "urn:dev:byid:usb:FTDI.Quad.RS232-HS:FT4ABCDE_if03_port0"},
+		{"usb-Arduino__www.arduino.cc__0043_75833353035351E0F0A1-if00", "urn:dev:byid:usb:Arduino.www.arduino.cc.0043:75833353035351E0F0A1_if00"},
+		{"usb-Generic_Flash_Disk_8B6F1C2A-0:0-part1", "urn:dev:byid:usb:Generic.Flash.Disk:8B6F1C2A_lun0.0_part1"},
+		{"ata-WDC_WD40EFRX-68N32N0_WD-WCC7K1234567", "urn:dev:byid:ata:WDC.WD40EFRX-68N32N0:WD-WCC7K1234567"},
+		{"ata-Samsung_SSD_860_EVO_500GB_S3Z9NB0K123456-part2", "urn:dev:byid:ata:Samsung.SSD.860.EVO.500GB:S3Z9NB0K123456_part2"},
+		{"nvme-Samsung_SSD_970_EVO_Plus_1TB_S4EWNX0R123456", "urn:dev:byid:nvme:Samsung.SSD.970.EVO.Plus.1TB:S4EWNX0R123456"},
+		{"mmc-SD32G_0x1234abcd", "urn:dev:byid:mmc:SD32G:0x1234abcd"},
+		{"scsi-SATA_ST4000NM000A-2HZ_ZC12ABCD", "urn:dev:byid:scsi:ATA.ST4000NM000A-2HZ:ZC12ABCD"},
+		{"scsi-1ATA_ST4000NM000A-2HZ100_ZC12ABCD", "urn:dev:byid:scsi:ATA.ST4000NM000A-2HZ100:ZC12ABCD"},
+		{"nvme-eui.0025385b71b07e2f-part1", "urn:dev:mac:0025385b71b07e2f_part1"},
+		{"wwn-0x5000c500a1b2c3d4", "urn:dev:org:32473-naa.5000c500a1b2c3d4"},
+		{"scsi-35000c500a1b2c3d4-part3", "urn:dev:org:32473-naa.5000c500a1b2c3d4_part3"},
+	} {
+		name, err := Parse(test.name)
+		if err != nil {
+			t.Fatalf("Failed to parse %s", test.name)
+			return
+		}
+
+		devUrn, err := name.ToUrnDev(map[string]int{"000c50": 32473})
+		if err != nil {
+			t.Fatalf("Failed to map %s", test.name)
+			return
+		}
+		assert.Equal(t, test.urn, devUrn.FullName, test.name)
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	name, err := Parse("wwn-0x5000c500a1b2c3d4-part1")
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.True(t, name.HasWWN)
+	assert.Equal(t, "naa.5000c500a1b2c3d4", name.WWN.String())
+	assert.Equal(t, []string{"part1"}, name.Components())
+
+	_, err = name.ToUrnDev(nil)
+	assert.NotNil(t, err)
+
+	name, _ = Parse("usb-0403_6001")
+	assert.Equal(t, Name{Bus: "usb", Model: "0403", Serial: "6001"}, name)
+	assert.Equal(t, []string{}, name.Components())
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, input := range []string{"", "usb", "usb-", "Usb-FTDI_A50285BI", "-FTDI_A50285BI", "usb-FTDI_-if00", "wwn-0x1234", "nvme-eui.00", "scsi-3600"} {
+		_, err := Parse(input)
+		assert.NotNil(t, err, input)
+	}
+}
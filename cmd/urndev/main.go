@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Command urndev provides tools for working with urn:dev device identifiers from the command line.
+//
+// Usage:
+//
+//	urndev grep [-b] [-c] [file ...]
+//
+// The grep mode prints every valid urn:dev found in the files, or in standard input when no files are given, one per line. Names are printed as found, with URL encoding decoded and wrapped lines joined, see rfc9039.FindAll. Option -b prefixes each name with its byte offset and -c prints only the number of names found. When several files are searched each line is prefixed with the file name. Exit status is 0 when names were found, 1 when none were found and 2 on error.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+const usage = "usage: urndev grep [-b] [-c] [file ...]"
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "grep" {
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+
+	flags := flag.NewFlagSet("grep", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	offsets := flags.Bool("b", false, "print byte offset of each name")
+	count := flags.Bool("c", false, "print only the number of names found")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	files := flags.Args()
+	found := 0
+	status := 0
+
+	if len(files) == 0 {
+		n, err := grep(stdin, "", *offsets, *count, stdout)
+		found += n
+		if err != nil {
+			fmt.Fprintf(stderr, "urndev: %v\n", err)
+			status = 2
+		}
+	}
+
+	for _, name := range files {
+		prefix := ""
+		if len(files) > 1 {
+			prefix = name + ":"
+		}
+
+		n, err := grepFile(name, prefix, *offsets, *count, stdout)
+		found += n
+		if err != nil {
+			fmt.Fprintf(stderr, "urndev: %v\n", err)
+			status = 2
+		}
+	}
+
+	if status == 0 && found == 0 {
+		status = 1
+	}
+
+	return status
+}
+
+func grepFile(name string, prefix string, offsets bool, count bool, stdout io.Writer) (int, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	return grep(file, prefix, offsets, count, stdout)
+}
+
+// grep writes names found from reader to stdout and returns the number of names found.
+func grep(reader io.Reader, prefix string, offsets bool, count bool, stdout io.Writer) (int, error) {
+	scanner := rfc9039.NewScanner(reader)
+	found := 0
+
+	for scanner.Scan() {
+		found++
+		if count {
+			continue
+		}
+
+		match := scanner.Match()
+		if offsets {
+			fmt.Fprintf(stdout, "%s%d:%s\n", prefix, match.Start, match.UrnDev.FullName)
+		} else {
+			fmt.Fprintf(stdout, "%s%s\n", prefix, match.UrnDev.FullName)
+		}
+	}
+
+	if count {
+		fmt.Fprintf(stdout, "%s%d\n", prefix, found)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return found, errors.New("invalid input (read failed: " + err.Error() + ")")
+	}
+
+	return found, nil
+}
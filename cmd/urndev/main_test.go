@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGrepStdin(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	stdin := strings.NewReader("door urn:dev:ops:32473-Refrigerator-5002 opened\nid=urn%3Adev%3Amac%3A0024befffe804ff1\n")
+
+	assert.Equal(t, 0, run([]string{"grep"}, stdin, &stdout, &stderr))
+	assert.Equal(t, "urn:dev:ops:32473-Refrigerator-5002\nurn:dev:mac:0024befffe804ff1\n", stdout.String())
+
+	stdout.Reset()
+	stdin.Seek(0, 0)
+	assert.Equal(t, 0, run([]string{"grep", "-b"}, stdin, &stdout, &stderr))
+	assert.Equal(t, "5:urn:dev:ops:32473-Refrigerator-5002\n51:urn:dev:mac:0024befffe804ff1\n", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 1, run([]string{"grep"}, strings.NewReader("nothing here"), &stdout, &stderr))
+	assert.Equal(t, "", stdout.String())
+}
+
+func TestGrepFiles(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.log")
+	second := filepath.Join(dir, "second.log")
+	if err := os.WriteFile(first, []byte("urn:dev:os:32473-5002, urn:dev:ow:10e2073a01080063\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write")
+		return
+	}
+	if err := os.WriteFile(second, []byte("none\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write")
+		return
+	}
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"grep", "-c", first, second}, nil, &stdout, &stderr))
+	assert.Equal(t, first+":2\n"+second+":0\n", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 2, run([]string{"grep", filepath.Join(dir, "missing.log")}, nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "missing.log")
+
+	stderr.Reset()
+	assert.Equal(t, 2, run([]string{"find"}, nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "usage")
+}
@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package devid provides tools for extracting IEEE 802.1AR device identities from X.509 certificates.
+package devid
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"strings"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+// OIDHardwareModuleName is the otherName type identifier of hardwareModuleName specified in RFC 4108.
+var OIDHardwareModuleName = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 8, 4}
+
+// OIDSubjectAltName is the subject alternative name extension identifier.
+var OIDSubjectAltName = asn1.ObjectIdentifier{2, 5, 29, 17}
+
+// HardwareModuleName captures RFC 4108 hardwareModuleName otherName.
+type HardwareModuleName struct {
+	// Type identifies the kind of hardware module, assigned by the manufacturer.
+	Type asn1.ObjectIdentifier
+	// SerialNumber is the serial number of the hardware module.
+	SerialNumber []byte
+}
+
+// Identity captures device identity information found in a certificate.
+type Identity struct {
+	// UrnDev captures all urn:dev URI subject alternative names.
+	UrnDev []rfc9039.UrnDev
+	// HardwareModuleName captures all hardwareModuleName subject alternative names.
+	HardwareModuleName []HardwareModuleName
+	// SerialNumber captures value of subject serialNumber attribute.
+	SerialNumber string
+}
+
+// hardwareModuleName is the ASN.1 form of HardwareModuleName.
+type hardwareModuleName struct {
+	HwType      asn1.ObjectIdentifier
+	HwSerialNum []byte
+}
+
+// parseHardwareModuleNames walks subject alternative name extension and collects hardwareModuleName otherNames which crypto/x509 does not expose.
+func parseHardwareModuleNames(cert *x509.Certificate) ([]HardwareModuleName, error) {
+	out := []HardwareModuleName{}
+
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(OIDSubjectAltName) {
+			continue
+		}
+
+		var names asn1.RawValue
+		if rest, err := asn1.Unmarshal(ext.Value, &names); err != nil || len(rest) > 0 {
+			return nil, errors.New("invalid input (subject alternative name)")
+		}
+
+		for rest := names.Bytes; len(rest) > 0; {
+			var name asn1.RawValue
+			var err error
+			if rest, err = asn1.Unmarshal(rest, &name); err != nil {
+				return nil, errors.New("invalid input (subject alternative name)")
+			}
+
+			// otherName [0] IMPLICIT SEQUENCE { type-id OBJECT IDENTIFIER, value [0] EXPLICIT ANY }
+			if name.Class != asn1.ClassContextSpecific || name.Tag != 0 {
+				continue
+			}
+
+			var typeID asn1.ObjectIdentifier
+			value, err := asn1.Unmarshal(name.Bytes, &typeID)
+			if err != nil {
+				return nil, errors.New("invalid input (otherName)")
+			}
+
+			if !typeID.Equal(OIDHardwareModuleName) {
+				continue
+			}
+
+			var explicit asn1.RawValue
+			if _, err := asn1.UnmarshalWithParams(value, &explicit, "explicit,tag:0"); err != nil {
+				return nil, errors.New("invalid input (hardwareModuleName)")
+			}
+
+			var hmn hardwareModuleName
+			if _, err := asn1.Unmarshal(explicit.Bytes, &hmn); err != nil {
+				return nil, errors.New("invalid input (hardwareModuleName)")
+			}
+
+			out = append(out, HardwareModuleName{Type: hmn.HwType, SerialNumber: hmn.HwSerialNum})
+		}
+	}
+
+	return out, nil
+}
+
+// Extract collects device identity from certificate. URI subject alternative names with urn:dev prefix are parsed with rfc9039.Parse, other URIs are ignored. An error is returned if any urn:dev URI is incorrectly formed or the identity is not consistent, see Identity.Validate.
+func Extract(cert *x509.Certificate) (Identity, error) {
+	out := Identity{UrnDev: []rfc9039.UrnDev{}}
+
+	for _, uri := range cert.URIs {
+		name := uri.String()
+		if !rfc9039.HasUrnDevPrefix(name) {
+			continue
+		}
+
+		devUrn, err := rfc9039.Parse(name)
+		if err != nil {
+			return Identity{}, err
+		}
+
+		out.UrnDev = append(out.UrnDev, devUrn)
+	}
+
+	var err error
+	out.HardwareModuleName, err = parseHardwareModuleNames(cert)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	out.SerialNumber = cert.Subject.SerialNumber
+
+	if err := out.Validate(); err != nil {
+		return Identity{}, err
+	}
+
+	return out, nil
+}
+
+// Validate checks that identity information refers to a single device: subject serialNumber, hardwareModuleName serial numbers and Serial of "os" and "ops" urn:dev names need to be equal when present, and urn:dev names of the same subtype need to identify the same device, differing at most in their component part. Names of different subtypes, e.g. mac and ops, cannot be compared and are accepted. An error is returned if no identity information is present.
+func (i Identity) Validate() error {
+	if len(i.UrnDev) == 0 && len(i.HardwareModuleName) == 0 && i.SerialNumber == "" {
+		return errors.New("invalid input (no identity)")
+	}
+
+	serial := []byte(i.SerialNumber)
+
+	for _, hmn := range i.HardwareModuleName {
+		if len(serial) == 0 {
+			serial = hmn.SerialNumber
+		} else if !bytes.Equal(serial, hmn.SerialNumber) {
+			return errors.New("inconsistent identity (hardwareModuleName serial)")
+		}
+	}
+
+	for _, devUrn := range i.UrnDev {
+		if devUrn.Serial == "" {
+			continue
+		}
+
+		if len(serial) == 0 {
+			serial = []byte(devUrn.Serial)
+		} else if string(serial) != devUrn.Serial {
+			return errors.New("inconsistent identity (urn:dev serial)")
+		}
+	}
+
+	devices := map[string]string{}
+	for _, devUrn := range i.UrnDev {
+		if device, ok := devices[devUrn.Subtype]; ok && device != deviceName(devUrn) {
+			return errors.New("inconsistent identity (urn:dev)")
+		}
+		devices[devUrn.Subtype] = deviceName(devUrn)
+	}
+
+	return nil
+}
+
+// deviceName returns urn:dev name without component part.
+func deviceName(devUrn rfc9039.UrnDev) string {
+	name := devUrn.FullName
+	if index := strings.IndexByte(name, '_'); index >= 0 {
+		name = name[:index]
+	}
+
+	return name
+}
+
+// Device returns the urn:dev name of the device itself, i.e. the first urn:dev name without its component part. False is returned when certificate did not carry any urn:dev name.
+func (i Identity) Device() (rfc9039.UrnDev, bool) {
+	if len(i.UrnDev) == 0 {
+		return rfc9039.UrnDev{}, false
+	}
+
+	devUrn, err := rfc9039.Parse(deviceName(i.UrnDev[0]))
+
+	return devUrn, err == nil
+}
@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package devid
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+	"time"
+)
+
+var testHwType = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 32473, 1}
+
+// marshalSAN builds subject alternative name extension with hardwareModuleName otherNames and URIs. It is kept independent of marshalSubjectAltName so that tests do not check the encoder against itself.
+func marshalSAN(t *testing.T, hmns []HardwareModuleName, uris []string) pkix.Extension {
+	var names []asn1.RawValue
+
+	for _, hmn := range hmns {
+		value, err := asn1.Marshal(hardwareModuleName{HwType: hmn.Type, HwSerialNum: hmn.SerialNumber})
+		assert.NoError(t, err)
+
+		explicit, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: value})
+		assert.NoError(t, err)
+
+		typeID, err := asn1.Marshal(OIDHardwareModuleName)
+		assert.NoError(t, err)
+
+		names = append(names, asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: append(typeID, explicit...)})
+	}
+
+	for _, uri := range uris {
+		names = append(names, asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 6, Bytes: []byte(uri)})
+	}
+
+	value, err := asn1.Marshal(names)
+	assert.NoError(t, err)
+
+	return pkix.Extension{Id: OIDSubjectAltName, Value: value}
+}
+
+func createCertificate(t *testing.T, serialNumber string, extensions []pkix.Extension) *x509.Certificate {
+	template := &x509.Certificate{
+		Subject:         pkix.Name{CommonName: "device", SerialNumber: serialNumber},
+		ExtraExtensions: extensions,
+	}
+
+	return signTemplate(t, template)
+}
+
+// signTemplate creates self-signed certificate from template.
+func signTemplate(t *testing.T, template *x509.Certificate) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key")
+	}
+
+	template.SerialNumber = big.NewInt(1)
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+
+	return cert
+}
+
+func TestExtract(t *testing.T) {
+	cert := createCertificate(t, "5002", []pkix.Extension{
+		marshalSAN(t,
+			[]HardwareModuleName{{Type: testHwType, SerialNumber: []byte("5002")}},
+			[]string{"https://example.com/device", "urn:dev:ops:32473-Refrigerator-5002", "urn:dev:ops:32473-Refrigerator-5002_compressor"}),
+	})
+
+	value, err := Extract(cert)
+	if err != nil {
+		t.Fatalf("Failed to extract: %v", err)
+		return
+	}
+	assert.Equal(t, "5002", value.SerialNumber)
+	assert.Equal(t, []HardwareModuleName{{Type: testHwType, SerialNumber: []byte("5002")}}, value.HardwareModuleName)
+	assert.Equal(t, 2, len(value.UrnDev))
+	assert.Equal(t, "urn:dev:ops:32473-Refrigerator-5002", value.UrnDev[0].FullName)
+	assert.Equal(t, []string{"compressor"}, value.UrnDev[1].Component)
+
+	device, ok := value.Device()
+	assert.True(t, ok)
+	assert.Equal(t, "urn:dev:ops:32473-Refrigerator-5002", device.FullName)
+}
+
+func TestExtractUrnDevOnly(t *testing.T) {
+	cert := createCertificate(t, "", []pkix.Extension{
+		marshalSAN(t, nil, []string{"urn:dev:mac:0024befffe804ff1_eth0"}),
+	})
+
+	value, err := Extract(cert)
+	if err != nil {
+		t.Fatalf("Failed to extract: %v", err)
+		return
+	}
+	assert.Equal(t, "", value.SerialNumber)
+	assert.Equal(t, []HardwareModuleName{}, value.HardwareModuleName)
+
+	device, ok := value.Device()
+	assert.True(t, ok)
+	assert.Equal(t, "urn:dev:mac:0024befffe804ff1", device.FullName)
+}
+
+func TestExtractHardwareModuleNameOnly(t *testing.T) {
+	cert := createCertificate(t, "", []pkix.Extension{
+		marshalSAN(t, []HardwareModuleName{{Type: testHwType, SerialNumber: []byte{0x01, 0x02}}}, nil),
+	})
+
+	value, err := Extract(cert)
+	if err != nil {
+		t.Fatalf("Failed to extract: %v", err)
+		return
+	}
+	assert.Equal(t, []byte{0x01, 0x02}, value.HardwareModuleName[0].SerialNumber)
+
+	_, ok := value.Device()
+	assert.False(t, ok)
+}
+
+func TestExtractNoIdentity(t *testing.T) {
+	cert := createCertificate(t, "", nil)
+
+	_, err := Extract(cert)
+	assert.Error(t, err)
+}
+
+func TestExtractInvalidUrnDev(t *testing.T) {
+	cert := createCertificate(t, "", []pkix.Extension{
+		marshalSAN(t, nil, []string{"urn:dev:mac:0024befffe804ff"}),
+	})
+
+	_, err := Extract(cert)
+	assert.Error(t, err)
+}
+
+func TestExtractInconsistentSerial(t *testing.T) {
+	cert := createCertificate(t, "5003", []pkix.Extension{
+		marshalSAN(t, nil, []string{"urn:dev:os:32473-5002"}),
+	})
+
+	_, err := Extract(cert)
+	assert.Error(t, err)
+
+	cert = createCertificate(t, "5002", []pkix.Extension{
+		marshalSAN(t, []HardwareModuleName{{Type: testHwType, SerialNumber: []byte("5003")}}, nil),
+	})
+
+	_, err = Extract(cert)
+	assert.Error(t, err)
+}
+
+func TestExtractInconsistentUrnDev(t *testing.T) {
+	cert := createCertificate(t, "", []pkix.Extension{
+		marshalSAN(t, nil, []string{"urn:dev:mac:0024befffe804ff1", "urn:dev:mac:0024befffe804ff2"}),
+	})
+
+	_, err := Extract(cert)
+	assert.Error(t, err)
+
+	// Different subtypes cannot be compared
+	cert = createCertificate(t, "", []pkix.Extension{
+		marshalSAN(t, nil, []string{"urn:dev:mac:0024befffe804ff1", "urn:dev:os:32473-5002"}),
+	})
+
+	_, err = Extract(cert)
+	assert.NoError(t, err)
+}
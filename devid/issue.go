@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package devid
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"net/url"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+// serialOf returns the value placed into subject serialNumber for given urn:dev. Subtypes without a serial number return empty string.
+func serialOf(devUrn rfc9039.UrnDev) string {
+	switch devUrn.Subtype {
+	case "os", "ops":
+		return devUrn.Serial
+	case "mac":
+		return devUrn.Eui64Identifier
+	case "ow":
+		return devUrn.OwIdentifier
+	default:
+		return ""
+	}
+}
+
+// marshalSubjectAltName builds subject alternative name extension value from template name fields and hardwareModuleName otherNames. crypto/x509 cannot emit otherNames so the whole extension needs to be built here.
+func marshalSubjectAltName(template *x509.Certificate, hmns []HardwareModuleName) ([]byte, error) {
+	var names []asn1.RawValue
+
+	for _, hmn := range hmns {
+		value, err := asn1.Marshal(hardwareModuleName{HwType: hmn.Type, HwSerialNum: hmn.SerialNumber})
+		if err != nil {
+			return nil, err
+		}
+
+		explicit, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: value})
+		if err != nil {
+			return nil, err
+		}
+
+		typeID, err := asn1.Marshal(OIDHardwareModuleName)
+		if err != nil {
+			return nil, err
+		}
+
+		names = append(names, asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: append(typeID, explicit...)})
+	}
+
+	for _, email := range template.EmailAddresses {
+		names = append(names, asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 1, Bytes: []byte(email)})
+	}
+
+	for _, dns := range template.DNSNames {
+		names = append(names, asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 2, Bytes: []byte(dns)})
+	}
+
+	for _, uri := range template.URIs {
+		names = append(names, asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 6, Bytes: []byte(uri.String())})
+	}
+
+	for _, ip := range template.IPAddresses {
+		if ip4 := ip.To4(); ip4 != nil {
+			ip = ip4
+		}
+		names = append(names, asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 7, Bytes: ip})
+	}
+
+	return asn1.Marshal(names)
+}
+
+// PopulateTemplate places urn:dev into certificate template as URI subject alternative name and sets subject serialNumber to the serial of the device: Serial for "os" and "ops", EUI-64 for "mac" and 1-Wire address for "ow". Subject serialNumber already set in the template is kept. When hardwareModuleName is given it is added as otherName subject alternative name, in which case the whole extension is built by this function and must not be present in template ExtraExtensions. FullName of devUrn is parsed with rfc9039.Parse and the parsed fields are used instead of those given by the caller. An error is returned if FullName is not a valid urn:dev or the resulting identity is not consistent, see Identity.Validate, in which case template is not changed.
+func PopulateTemplate(template *x509.Certificate, name rfc9039.UrnDev, hmn *HardwareModuleName) error {
+	devUrn, err := rfc9039.Parse(name.FullName)
+	if err != nil {
+		return err
+	}
+
+	uri, err := url.Parse(devUrn.FullName)
+	if err != nil {
+		return errors.New("invalid input (urn:dev)")
+	}
+
+	serialNumber := template.Subject.SerialNumber
+	if serialNumber == "" {
+		serialNumber = serialOf(devUrn)
+	}
+
+	identity := Identity{UrnDev: []rfc9039.UrnDev{devUrn}, SerialNumber: serialNumber}
+	if hmn != nil {
+		identity.HardwareModuleName = []HardwareModuleName{*hmn}
+	}
+
+	if err := identity.Validate(); err != nil {
+		return err
+	}
+
+	// Everything is checked and encoded before the template is changed, so it is left as is on error
+	uris := append(append([]*url.URL{}, template.URIs...), uri)
+	extensions := template.ExtraExtensions
+
+	if hmn != nil {
+		for _, ext := range template.ExtraExtensions {
+			if ext.Id.Equal(OIDSubjectAltName) {
+				return errors.New("invalid input (subject alternative name already present)")
+			}
+		}
+
+		names := *template
+		names.URIs = uris
+		value, err := marshalSubjectAltName(&names, identity.HardwareModuleName)
+		if err != nil {
+			return err
+		}
+
+		extensions = append(append([]pkix.Extension{}, template.ExtraExtensions...), pkix.Extension{Id: OIDSubjectAltName, Value: value})
+	}
+
+	template.Subject.SerialNumber = serialNumber
+	template.URIs = uris
+	template.ExtraExtensions = extensions
+
+	return nil
+}
+
+// Verify checks that certificate carries a consistent identity which matches the expected urn:dev. Certificate issued for a device or a component also matches components below it, e.g. certificate for "urn:dev:ops:32473-Refrigerator-5002" matches "urn:dev:ops:32473-Refrigerator-5002_compressor" but not the other way around. The "urn:dev:" prefix is compared case insensitively and the rest of the name exactly. FullName of expected is parsed with rfc9039.Parse and an error is returned if it is not a valid urn:dev.
+func Verify(cert *x509.Certificate, name rfc9039.UrnDev) error {
+	expected, err := rfc9039.Parse(name.FullName)
+	if err != nil {
+		return err
+	}
+
+	identity, err := Extract(cert)
+	if err != nil {
+		return err
+	}
+
+	for _, devUrn := range identity.UrnDev {
+		if covers(devUrn, expected) {
+			return nil
+		}
+	}
+
+	return errors.New("certificate does not match (urn:dev)")
+}
+
+// covers checks whether name identifies the same device as expected and its component part is a prefix of the component part of expected. Both names need to be parsed with rfc9039.Parse.
+func covers(name rfc9039.UrnDev, expected rfc9039.UrnDev) bool {
+	if deviceName(name)[len(rfc9039.UrnDevPrefix):] != deviceName(expected)[len(rfc9039.UrnDevPrefix):] {
+		return false
+	}
+
+	if len(name.Component) > len(expected.Component) {
+		return false
+	}
+
+	for i, component := range name.Component {
+		if component != expected.Component[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package devid
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+func TestPopulateTemplate(t *testing.T) {
+	devUrn, _ := rfc9039.Parse("urn:dev:ops:32473-Refrigerator-5002")
+	template := &x509.Certificate{Subject: pkix.Name{CommonName: "device"}}
+
+	err := PopulateTemplate(template, devUrn, nil)
+	if err != nil {
+		t.Fatalf("Failed to populate: %v", err)
+		return
+	}
+	assert.Equal(t, "5002", template.Subject.SerialNumber)
+	assert.Equal(t, "urn:dev:ops:32473-Refrigerator-5002", template.URIs[0].String())
+
+	value, err := Extract(signTemplate(t, template))
+	if err != nil {
+		t.Fatalf("Failed to extract: %v", err)
+		return
+	}
+	assert.Equal(t, "5002", value.SerialNumber)
+	assert.Equal(t, []HardwareModuleName{}, value.HardwareModuleName)
+	assert.Equal(t, "urn:dev:ops:32473-Refrigerator-5002", value.UrnDev[0].FullName)
+}
+
+func TestPopulateTemplateWithHardwareModuleName(t *testing.T) {
+	devUrn, _ := rfc9039.Parse("urn:dev:mac:0024befffe804ff1")
+	hmn := HardwareModuleName{Type: testHwType, SerialNumber: []byte("0024befffe804ff1")}
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "device"},
+		DNSNames:    []string{"device.example.com"},
+		IPAddresses: []net.IP{net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1")},
+	}
+
+	err := PopulateTemplate(template, devUrn, &hmn)
+	if err != nil {
+		t.Fatalf("Failed to populate: %v", err)
+		return
+	}
+	assert.Equal(t, "0024befffe804ff1", template.Subject.SerialNumber)
+
+	cert := signTemplate(t, template)
+	assert.Equal(t, []string{"device.example.com"}, cert.DNSNames)
+	assert.Equal(t, 2, len(cert.IPAddresses))
+
+	value, err := Extract(cert)
+	if err != nil {
+		t.Fatalf("Failed to extract: %v", err)
+		return
+	}
+	assert.Equal(t, []HardwareModuleName{hmn}, value.HardwareModuleName)
+	assert.Equal(t, "urn:dev:mac:0024befffe804ff1", value.UrnDev[0].FullName)
+}
+
+func TestPopulateTemplateInvalid(t *testing.T) {
+	devUrn, _ := rfc9039.Parse("urn:dev:os:32473-5002")
+
+	// Serial number already in template does not match
+	template := &x509.Certificate{Subject: pkix.Name{SerialNumber: "5003"}}
+	assert.Error(t, PopulateTemplate(template, devUrn, nil))
+	assert.Equal(t, 0, len(template.URIs))
+
+	// hardwareModuleName serial does not match, template is left as is
+	template = &x509.Certificate{}
+	assert.Error(t, PopulateTemplate(template, devUrn, &HardwareModuleName{Type: testHwType, SerialNumber: []byte("5003")}))
+	assert.Equal(t, &x509.Certificate{}, template)
+
+	// Subject alternative name extension already present
+	template = &x509.Certificate{ExtraExtensions: []pkix.Extension{{Id: OIDSubjectAltName}}}
+	assert.Error(t, PopulateTemplate(template, devUrn, &HardwareModuleName{Type: testHwType, SerialNumber: []byte("5002")}))
+	assert.Equal(t, &x509.Certificate{ExtraExtensions: []pkix.Extension{{Id: OIDSubjectAltName}}}, template)
+
+	// Not a valid urn:dev
+	for _, name := range []string{"", "urn:dev:", "urn:dev:ops:garbage%%"} {
+		template = &x509.Certificate{}
+		assert.Error(t, PopulateTemplate(template, rfc9039.UrnDev{FullName: name}, nil), name)
+		assert.Equal(t, &x509.Certificate{}, template, name)
+	}
+}
+
+func TestPopulateTemplateUnparsed(t *testing.T) {
+	// Fields are taken from the parsed name, not from the given value
+	template := &x509.Certificate{}
+	err := PopulateTemplate(template, rfc9039.UrnDev{FullName: "urn:dev:ops:32473-Refrigerator-5002"}, nil)
+	if err != nil {
+		t.Fatalf("Failed to populate: %v", err)
+		return
+	}
+	assert.Equal(t, "5002", template.Subject.SerialNumber)
+
+	// hardwareModuleName serial is validated against the parsed serial
+	template = &x509.Certificate{}
+	err = PopulateTemplate(template, rfc9039.UrnDev{FullName: "urn:dev:os:32473-5002"}, &HardwareModuleName{Type: testHwType, SerialNumber: []byte("5003")})
+	assert.Error(t, err)
+	assert.Equal(t, &x509.Certificate{}, template)
+}
+
+func TestMarshalSubjectAltName(t *testing.T) {
+	// SEQUENCE { otherName [0] { id-on-hardwareModuleName, [0] EXPLICIT SEQUENCE { 1.3.6.1.4.1.32473.1, OCTET STRING "5002" } }, [6] "urn:dev:os:32473-5002" }
+	expected, _ := hex.DecodeString("3038" +
+		"a01f" + "06082b06010505070804" + "a013" + "3011" + "06092b0601040181fd5901" + "040435303032" +
+		"8615" + hex.EncodeToString([]byte("urn:dev:os:32473-5002")))
+
+	uri, _ := url.Parse("urn:dev:os:32473-5002")
+	value, err := marshalSubjectAltName(&x509.Certificate{URIs: []*url.URL{uri}}, []HardwareModuleName{{Type: testHwType, SerialNumber: []byte("5002")}})
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+		return
+	}
+	assert.Equal(t, expected, value)
+}
+
+func TestVerify(t *testing.T) {
+	devUrn, _ := rfc9039.Parse("urn:dev:ops:32473-Refrigerator-5002_compressor")
+	template := &x509.Certificate{}
+	if err := PopulateTemplate(template, devUrn, nil); err != nil {
+		t.Fatalf("Failed to populate: %v", err)
+		return
+	}
+	cert := signTemplate(t, template)
+
+	for _, name := range []string{
+		"urn:dev:ops:32473-Refrigerator-5002_compressor",
+		"URN:DEV:ops:32473-Refrigerator-5002_compressor",
+		"urn:dev:ops:32473-Refrigerator-5002_compressor_motor",
+	} {
+		expected, _ := rfc9039.Parse(name)
+		assert.NoError(t, Verify(cert, expected), name)
+	}
+
+	for _, name := range []string{
+		"urn:dev:ops:32473-Refrigerator-5002",
+		"urn:dev:ops:32473-Refrigerator-5002_fan",
+		"urn:dev:ops:32473-Refrigerator-5003_compressor",
+		"urn:dev:ops:32473-refrigerator-5002_compressor",
+	} {
+		expected, _ := rfc9039.Parse(name)
+		assert.Error(t, Verify(cert, expected), name)
+	}
+
+	// Expected name is parsed, fields given by the caller are not trusted
+	assert.NoError(t, Verify(cert, rfc9039.UrnDev{FullName: "urn:dev:ops:32473-Refrigerator-5002_compressor_motor"}))
+	assert.Error(t, Verify(cert, rfc9039.UrnDev{FullName: "urn:dev:ops:32473-Refrigerator-5002", Component: []string{"compressor"}}))
+
+	for _, name := range []string{"", "urn:dev:", "urn:dev:ops:garbage%%"} {
+		assert.Error(t, Verify(cert, rfc9039.UrnDev{FullName: name}), name)
+	}
+}
+
+func TestVerifyInconsistentCertificate(t *testing.T) {
+	cert := createCertificate(t, "5003", []pkix.Extension{
+		marshalSAN(t, nil, []string{"urn:dev:os:32473-5002"}),
+	})
+	expected, _ := rfc9039.Parse("urn:dev:os:32473-5002")
+
+	assert.Error(t, Verify(cert, expected))
+}
@@ -0,0 +1,337 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package discovery provides tools for discovering urn:dev identifiers of the local machine from Linux sysfs.
+package discovery
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+	"github.com/RisingEdgeSolutions/device-identifiers/vidpid"
+)
+
+const MacAddressRegEx = "^([0-9a-f]{2}):([0-9a-f]{2}):([0-9a-f]{2}):([0-9a-f]{2}):([0-9a-f]{2}):([0-9a-f]{2})$"
+const OneWireNameRegEx = "^([0-9a-f]{2})-([0-9a-f]{12})$"
+
+var macAddressRegEx = regexp.MustCompile(MacAddressRegEx)
+var oneWireNameRegEx = regexp.MustCompile(OneWireNameRegEx)
+
+// invalidCharactersRegEx matches runs of characters not valid in urn:dev identifiers.
+var invalidCharactersRegEx = regexp.MustCompile("[^A-Za-z0-9\\.\\-]+")
+
+// addrAssignPermanent is the addr_assign_type of addresses burned into hardware (NET_ADDR_PERM).
+const addrAssignPermanent = "0"
+
+// arphrdEther is the type of Ethernet interfaces (ARPHRD_ETHER).
+const arphrdEther = "1"
+
+// dmiPlaceholders are values firmware commonly leaves in DMI fields which are not set. They are compared in lower case.
+var dmiPlaceholders = map[string]bool{
+	"":                       true,
+	"0":                      true,
+	"0123456789":             true,
+	"default string":         true,
+	"none":                   true,
+	"not applicable":         true,
+	"not specified":          true,
+	"o.e.m.":                 true,
+	"system manufacturer":    true,
+	"system product name":    true,
+	"system serial number":   true,
+	"to be filled by o.e.m.": true,
+	"unknown":                true,
+}
+
+// Source defines where in sysfs an identifier was discovered.
+type Source int
+
+const (
+	Network Source = iota
+	OneWire
+	DMI
+	USB
+)
+
+// String returns name of the source.
+func (s Source) String() string {
+	switch s {
+	case Network:
+		return "net"
+	case OneWire:
+		return "w1"
+	case DMI:
+		return "dmi"
+	case USB:
+		return "usb"
+	default:
+		return "unknown"
+	}
+}
+
+// Identifier captures single discovered identifier.
+type Identifier struct {
+	// Source is the kind of sysfs entry the identifier was read from.
+	Source Source
+	// Path is the sysfs directory the identifier was read from, relative to the root of the file system, for example "class/net/eth0".
+	Path string
+	// UrnDev is the identifier of the device.
+	UrnDev rfc9039.UrnDev
+}
+
+// readValue reads sysfs attribute file with surrounding white space trimmed.
+func readValue(fsys fs.FS, name string) (string, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// readDir lists directory names, missing directory is not an error as the devices may not be present.
+func readDir(fsys fs.FS, name string) ([]string, error) {
+	entries, err := fs.ReadDir(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func exists(fsys fs.FS, name string) bool {
+	_, err := fs.Stat(fsys, name)
+
+	return err == nil
+}
+
+// Discover collects identifiers from network interfaces, 1-Wire slaves, DMI and USB devices, in that order, from fsys rooted at /sys, e.g. os.DirFS("/sys"). Entries which can not be read, for example due to permissions, are skipped and an error is returned only when listing a directory fails.
+func Discover(fsys fs.FS) ([]Identifier, error) {
+	out := []Identifier{}
+
+	for _, discover := range []func(fs.FS) ([]Identifier, error){NetworkInterfaces, OneWireDevices, DMIIdentifiers, USBDevices} {
+		identifiers, err := discover(fsys)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, identifiers...)
+	}
+
+	return out, nil
+}
+
+// NetworkInterfaces maps MAC addresses of physical Ethernet and wireless interfaces in class/net into urn:dev:mac identifiers with EUI-48 converted into EUI-64 by inserting "fffe". Interfaces without "device" link, such as loopback, bridges, tunnels and veth pairs, are virtual and skipped. Also addresses which are not permanent according to addr_assign_type, e.g. randomized or set by user space, and locally administered, multicast and zero addresses are skipped. Interfaces sharing the same address, e.g. bonded ones, are reported once.
+func NetworkInterfaces(fsys fs.FS) ([]Identifier, error) {
+	names, err := readDir(fsys, "class/net")
+	if err != nil {
+		return nil, err
+	}
+
+	out := []Identifier{}
+	seen := map[string]bool{}
+
+	for _, name := range names {
+		dir := path.Join("class/net", name)
+
+		if !exists(fsys, path.Join(dir, "device")) {
+			continue
+		}
+
+		if value, err := readValue(fsys, path.Join(dir, "type")); err != nil || value != arphrdEther {
+			continue
+		}
+
+		if value, err := readValue(fsys, path.Join(dir, "addr_assign_type")); err == nil && value != addrAssignPermanent {
+			continue
+		}
+
+		address, err := readValue(fsys, path.Join(dir, "address"))
+		if err != nil {
+			continue
+		}
+
+		match := macAddressRegEx.FindStringSubmatch(strings.ToLower(address))
+		if match == nil {
+			continue
+		}
+
+		first, _ := strconv.ParseUint(match[1], 16, 8)
+		if first&0x03 != 0 || strings.Join(match[1:], "") == "000000000000" {
+			continue
+		}
+
+		eui64 := match[1] + match[2] + match[3] + "fffe" + match[4] + match[5] + match[6]
+		if seen[eui64] {
+			continue
+		}
+		seen[eui64] = true
+
+		devUrn, err := rfc9039.Parse(rfc9039.UrnDevPrefix + "mac:" + eui64)
+		if err != nil {
+			continue
+		}
+
+		out = append(out, Identifier{Source: Network, Path: dir, UrnDev: devUrn})
+	}
+
+	return out, nil
+}
+
+// OwCRC calculates Dallas/Maxim CRC-8 used as the last byte of 1-Wire addresses.
+func OwCRC(data []byte) byte {
+	crc := byte(0)
+
+	for _, value := range data {
+		for bit := 0; bit < 8; bit++ {
+			mix := (crc ^ value) & 0x01
+			crc >>= 1
+			if mix != 0 {
+				crc ^= 0x8c
+			}
+			value >>= 1
+		}
+	}
+
+	return crc
+}
+
+// OneWireDevices maps 1-Wire slaves in bus/w1/devices into urn:dev:ow identifiers. The 64-bit address is read from the "id" attribute in bus order: family code, 48-bit serial least significant byte first and CRC. Without the attribute the address is built from the "28-00000a1b2c3d" style directory name with CRC calculated. Bus masters and addresses with invalid CRC are skipped.
+func OneWireDevices(fsys fs.FS) ([]Identifier, error) {
+	names, err := readDir(fsys, "bus/w1/devices")
+	if err != nil {
+		return nil, err
+	}
+
+	out := []Identifier{}
+
+	for _, name := range names {
+		dir := path.Join("bus/w1/devices", name)
+
+		match := oneWireNameRegEx.FindStringSubmatch(name)
+		if match == nil {
+			continue
+		}
+
+		address, err := fs.ReadFile(fsys, path.Join(dir, "id"))
+		if err != nil || len(address) != 8 {
+			family, _ := hex.DecodeString(match[1])
+			serial, _ := hex.DecodeString(match[2])
+
+			address = family
+			for index := len(serial) - 1; index >= 0; index-- {
+				address = append(address, serial[index])
+			}
+			address = append(address, OwCRC(address))
+		}
+
+		if OwCRC(address[:7]) != address[7] {
+			continue
+		}
+
+		devUrn, err := rfc9039.Parse(rfc9039.UrnDevPrefix + "ow:" + hex.EncodeToString(address))
+		if err != nil {
+			continue
+		}
+
+		out = append(out, Identifier{Source: OneWire, Path: dir, UrnDev: devUrn})
+	}
+
+	return out, nil
+}
+
+// identifier converts DMI string into urn:dev identifier by replacing runs of characters not valid in urn:dev with "-". Placeholder values left by firmware give an empty string.
+func identifier(value string) string {
+	value = strings.TrimSpace(value)
+	if dmiPlaceholders[strings.ToLower(value)] {
+		return ""
+	}
+
+	return strings.Trim(invalidCharactersRegEx.ReplaceAllString(value, "-"), "-")
+}
+
+// DMIIdentifiers maps system vendor, product name and serial number in class/dmi/id into urn:dev otherbody identifier with "dmi" subtype, for example "urn:dev:dmi:LENOVO:20XW0055GE:PF2ABCDE". Characters not valid in urn:dev are replaced with "-". product_serial is readable only by root, so nothing is returned for other users, and nothing is returned when any of the values is missing or a placeholder such as "To Be Filled By O.E.M.".
+func DMIIdentifiers(fsys fs.FS) ([]Identifier, error) {
+	dir := "class/dmi/id"
+	parts := []string{}
+
+	for _, name := range []string{"sys_vendor", "product_name", "product_serial"} {
+		value, err := readValue(fsys, path.Join(dir, name))
+		if err != nil {
+			return []Identifier{}, nil
+		}
+
+		part := identifier(value)
+		if part == "" {
+			return []Identifier{}, nil
+		}
+		parts = append(parts, part)
+	}
+
+	devUrn, err := rfc9039.Parse(rfc9039.UrnDevPrefix + "dmi:" + strings.Join(parts, ":"))
+	if err != nil {
+		return []Identifier{}, nil
+	}
+
+	return []Identifier{{Source: DMI, Path: dir, UrnDev: devUrn}}, nil
+}
+
+// USBDevices maps USB devices in bus/usb/devices having a serial number into urn:dev:usb identifiers, see vidpid.ID.ToUrnDev. Root hubs, whose serial number is the host controller address, and interfaces are skipped.
+func USBDevices(fsys fs.FS) ([]Identifier, error) {
+	names, err := readDir(fsys, "bus/usb/devices")
+	if err != nil {
+		return nil, err
+	}
+
+	out := []Identifier{}
+
+	for _, name := range names {
+		dir := path.Join("bus/usb/devices", name)
+
+		if strings.HasPrefix(name, "usb") || strings.Contains(name, ":") {
+			continue
+		}
+
+		values := []string{}
+		for _, attribute := range []string{"idVendor", "idProduct", "serial"} {
+			value, err := readValue(fsys, path.Join(dir, attribute))
+			if err != nil {
+				break
+			}
+			values = append(values, value)
+		}
+
+		if len(values) != 3 || values[2] == "" {
+			continue
+		}
+
+		id, err := vidpid.ParseID(vidpid.USB, fmt.Sprintf("%s:%s", values[0], values[1]))
+		if err != nil {
+			continue
+		}
+
+		devUrn, err := id.ToUrnDev(values[2])
+		if err != nil {
+			continue
+		}
+
+		out = append(out, Identifier{Source: USB, Path: dir, UrnDev: devUrn})
+	}
+
+	return out, nil
+}
@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package discovery
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"testing/fstest"
+)
+
+func file(data string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(data)}
+}
+
+func interfaceFiles(sys fstest.MapFS, name string, address string, assignType string, physical bool) {
+	sys["class/net/"+name+"/address"] = file(address + "\n")
+	sys["class/net/"+name+"/type"] = file("1\n")
+	sys["class/net/"+name+"/addr_assign_type"] = file(assignType + "\n")
+	if physical {
+		sys["class/net/"+name+"/device/uevent"] = file("DRIVER=e1000e\n")
+	}
+}
+
+func testSys() fstest.MapFS {
+	sys := fstest.MapFS{
+		"class/net/lo/address":                     file("00:00:00:00:00:00\n"),
+		"class/net/lo/type":                        file("772\n"),
+		"class/net/lo/addr_assign_type":            file("0\n"),
+		"bus/w1/devices/w1_bus_master1/uevent":     file(""),
+		"bus/w1/devices/10-0008013a07e2/id":        file("\x10\xe2\x07\x3a\x01\x08\x00\x63"),
+		"bus/w1/devices/28-00000a1b2c3d/name":      file("28-00000a1b2c3d\n"),
+		"bus/w1/devices/28-000000000001/id":        file("\x28\x01\x00\x00\x00\x00\x00\x00"),
+		"class/dmi/id/sys_vendor":                  file("LENOVO\n"),
+		"class/dmi/id/product_name":                file("20XW0055GE\n"),
+		"class/dmi/id/product_serial":              file("PF2ABCDE\n"),
+		"bus/usb/devices/usb1/idVendor":            file("1d6b\n"),
+		"bus/usb/devices/usb1/idProduct":           file("0002\n"),
+		"bus/usb/devices/usb1/serial":              file("0000:00:14.0\n"),
+		"bus/usb/devices/1-1/idVendor":             file("0403\n"),
+		"bus/usb/devices/1-1/idProduct":            file("6001\n"),
+		"bus/usb/devices/1-1/serial":               file("A50285BI\n"),
+		"bus/usb/devices/1-1:1.0/bInterfaceNumber": file("00\n"),
+		"bus/usb/devices/1-2/idVendor":             file("046d\n"),
+		"bus/usb/devices/1-2/idProduct":            file("c52b\n"),
+	}
+
+	interfaceFiles(sys, "eth0", "00:24:be:80:4f:f1", "0", true)
+	interfaceFiles(sys, "bond0", "00:24:be:80:4f:f1", "0", true)
+	interfaceFiles(sys, "wlan0", "8a:2f:5c:01:02:03", "1", true)
+	interfaceFiles(sys, "eth1", "02:42:ac:11:00:02", "0", true)
+	interfaceFiles(sys, "docker0", "00:1a:7d:da:71:13", "0", false)
+	interfaceFiles(sys, "eth2", "00:1a:7d:da:71:14", "3", true)
+
+	return sys
+}
+
+func ExampleDiscover() {
+	identifiers, _ := Discover(testSys())
+	for _, identifier := range identifiers {
+		fmt.Println(identifier.Source, identifier.Path, identifier.UrnDev.FullName)
+	}
+	// Output: net class/net/bond0 urn:dev:mac:0024befffe804ff1
+	// w1 bus/w1/devices/10-0008013a07e2 urn:dev:ow:10e2073a01080063
+	// w1 bus/w1/devices/28-00000a1b2c3d urn:dev:ow:283d2c1b0a0000a6
+	// dmi class/dmi/id urn:dev:dmi:LENOVO:20XW0055GE:PF2ABCDE
+	// usb bus/usb/devices/1-1 urn:dev:usb:0403-6001:A50285BI
+}
+
+func TestNetworkInterfaces(t *testing.T) {
+	sys := fstest.MapFS{}
+	interfaceFiles(sys, "enp0s31f6", "00:1A:7D:DA:71:13", "0", true)
+	interfaceFiles(sys, "wlp2s0", "01:00:5e:00:00:01", "0", true)
+	sys["class/net/ib0/address"] = file("80:00:02:08:fe:80:00:00:00:00:00:00:00:02:c9:03:00:0f:a1:b1\n")
+	sys["class/net/ib0/type"] = file("32\n")
+	sys["class/net/ib0/device/uevent"] = file("")
+
+	identifiers, err := NetworkInterfaces(sys)
+	if err != nil {
+		t.Fatalf("Failed to discover")
+		return
+	}
+	assert.Equal(t, 1, len(identifiers))
+	assert.Equal(t, "urn:dev:mac:001a7dfffeda7113", identifiers[0].UrnDev.FullName)
+	assert.Equal(t, "class/net/enp0s31f6", identifiers[0].Path)
+}
+
+func TestOwCRC(t *testing.T) {
+	assert.Equal(t, byte(0x63), OwCRC([]byte{0x10, 0xe2, 0x07, 0x3a, 0x01, 0x08, 0x00}))
+	assert.Equal(t, byte(0x00), OwCRC([]byte{0x10, 0xe2, 0x07, 0x3a, 0x01, 0x08, 0x00, 0x63}))
+}
+
+func TestDMIIdentifiers(t *testing.T) {
+	sys := fstest.MapFS{
+		"class/dmi/id/sys_vendor":     file("LENOVO\n"),
+		"class/dmi/id/product_name":   file("ThinkPad X1 Carbon (Gen 9)\n"),
+		"class/dmi/id/product_serial": file(" PF2 ABCDE \n"),
+	}
+
+	identifiers, err := DMIIdentifiers(sys)
+	if err != nil {
+		t.Fatalf("Failed to discover")
+		return
+	}
+	assert.Equal(t, 1, len(identifiers))
+	assert.Equal(t, "urn:dev:dmi:LENOVO:ThinkPad-X1-Carbon-Gen-9:PF2-ABCDE", identifiers[0].UrnDev.FullName)
+
+	// Missing parts are not made up
+	for _, vendor := range []string{"To Be Filled By O.E.M.", "System manufacturer", " "} {
+		sys["class/dmi/id/sys_vendor"] = file(vendor + "\n")
+		identifiers, _ := DMIIdentifiers(sys)
+		assert.Equal(t, 0, len(identifiers), vendor)
+	}
+	sys["class/dmi/id/sys_vendor"] = file("LENOVO\n")
+
+	for _, serial := range []string{"System Serial Number", "Default string", ""} {
+		sys["class/dmi/id/product_serial"] = file(serial + "\n")
+		identifiers, _ := DMIIdentifiers(sys)
+		assert.Equal(t, 0, len(identifiers), serial)
+	}
+
+	delete(sys, "class/dmi/id/product_serial")
+	identifiers, _ = DMIIdentifiers(sys)
+	assert.Equal(t, 0, len(identifiers))
+}
+
+func TestDiscoverEmpty(t *testing.T) {
+	identifiers, err := Discover(fstest.MapFS{})
+	if err != nil {
+		t.Fatalf("Failed to discover")
+		return
+	}
+	assert.Equal(t, 0, len(identifiers))
+	assert.Equal(t, "w1", OneWire.String())
+}
@@ -0,0 +1,521 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package epc provides tools for parsing GS1 EPC pure identity URIs and SGTIN tag encodings.
+package epc
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+const EpcIdPrefix = "urn:epc:id:"
+
+const DigitsRegEx = "^[0-9]+$"
+const CompanyPrefixRegEx = "^[0-9]{6,12}$"
+
+// Cs82RegEx matches GS1 AI encodable character set 82 used in serial numbers and asset references.
+const Cs82RegEx = "^[!\"%&'()*+,\\-./0-9:;<=>?A-Z_a-z]*$"
+
+// SGTIN is a Serialized Global Trade Item Number.
+type SGTIN struct {
+	// CompanyPrefix is the GS1 Company Prefix, 6 to 12 digits.
+	CompanyPrefix string
+	// ItemReference is the indicator digit followed by the item reference, together with CompanyPrefix 13 digits.
+	ItemReference string
+	// Serial is the serial number, 1 to 20 characters from GS1 character set 82.
+	Serial string
+}
+
+// SGLN is a Global Location Number with or without extension.
+type SGLN struct {
+	// CompanyPrefix is the GS1 Company Prefix, 6 to 12 digits.
+	CompanyPrefix string
+	// LocationReference is the location reference, together with CompanyPrefix 12 digits.
+	LocationReference string
+	// Extension is the GLN extension, "0" denotes no extension.
+	Extension string
+}
+
+// GIAI is a Global Individual Asset Identifier.
+type GIAI struct {
+	// CompanyPrefix is the GS1 Company Prefix, 6 to 12 digits.
+	CompanyPrefix string
+	// AssetReference is the individual asset reference, together with CompanyPrefix at most 30 characters.
+	AssetReference string
+}
+
+// GRAI is a Global Returnable Asset Identifier.
+type GRAI struct {
+	// CompanyPrefix is the GS1 Company Prefix, 6 to 12 digits.
+	CompanyPrefix string
+	// AssetType is the asset type, together with CompanyPrefix 12 digits.
+	AssetType string
+	// Serial is the optional serial number, at most 16 characters from GS1 character set 82.
+	Serial string
+}
+
+// uriEscapes lists characters of GS1 character set 82 that need to be escaped in EPC URIs.
+var uriEscapes = strings.NewReplacer(
+	"\"", "%22", "%", "%25", "&", "%26", "/", "%2F", "<", "%3C", ">", "%3E", "?", "%3F",
+)
+
+func escape(name string) string {
+	return uriEscapes.Replace(name)
+}
+
+func unescape(name string) (string, error) {
+	var out strings.Builder
+
+	for i := 0; i < len(name); i++ {
+		if name[i] != '%' {
+			out.WriteByte(name[i])
+			continue
+		}
+
+		if i+2 >= len(name) {
+			return "", errors.New("invalid input (escape)")
+		}
+
+		value, err := hex.DecodeString(name[i+1 : i+3])
+		if err != nil {
+			return "", errors.New("invalid input (escape)")
+		}
+		out.WriteByte(value[0])
+		i += 2
+	}
+
+	return out.String(), nil
+}
+
+func isValidDigits(name string) bool {
+	match, _ := regexp.MatchString(DigitsRegEx, name)
+
+	return match
+}
+
+func isValidCompanyPrefix(name string) bool {
+	match, _ := regexp.MatchString(CompanyPrefixRegEx, name)
+
+	return match
+}
+
+func isValidCs82(name string) bool {
+	match, _ := regexp.MatchString(Cs82RegEx, name)
+
+	return match
+}
+
+// splitURI checks the scheme prefix and splits remaining URI into "." separated fields. Escaped fields are unescaped.
+func splitURI(name string, scheme string, count int) ([]string, error) {
+	prefix := EpcIdPrefix + scheme + ":"
+	if !strings.HasPrefix(name, prefix) {
+		return nil, errors.New("invalid input (missing " + prefix + ")")
+	}
+
+	fields := strings.SplitN(name[len(prefix):], ".", count)
+	if len(fields) != count {
+		return nil, errors.New("invalid input (" + scheme + ")")
+	}
+
+	var err error
+	fields[count-1], err = unescape(fields[count-1])
+	if err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// GTINCheckDigit calculates GS1 mod 10 check digit for given digits.
+func GTINCheckDigit(digits string) byte {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		digit := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+// IsValidGTIN checks whether GTIN-8, GTIN-12, GTIN-13 or GTIN-14 has correct length and check digit.
+func IsValidGTIN(gtin string) bool {
+	switch len(gtin) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+
+	if !isValidDigits(gtin) {
+		return false
+	}
+
+	return GTINCheckDigit(gtin[:len(gtin)-1]) == gtin[len(gtin)-1]
+}
+
+func (s SGTIN) validate() error {
+	if !isValidCompanyPrefix(s.CompanyPrefix) {
+		return errors.New("invalid input (company prefix)")
+	}
+
+	if !isValidDigits(s.ItemReference) || len(s.CompanyPrefix)+len(s.ItemReference) != 13 {
+		return errors.New("invalid input (item reference)")
+	}
+
+	if len(s.Serial) < 1 || len(s.Serial) > 20 || !isValidCs82(s.Serial) {
+		return errors.New("invalid input (serial)")
+	}
+
+	return nil
+}
+
+// ParseSGTIN parses SGTIN pure identity URI, for example "urn:epc:id:sgtin:0614141.812345.6789". If incorrectly formed URI is given as input an error is returned.
+func ParseSGTIN(name string) (SGTIN, error) {
+	fields, err := splitURI(name, "sgtin", 3)
+	if err != nil {
+		return SGTIN{}, err
+	}
+
+	out := SGTIN{CompanyPrefix: fields[0], ItemReference: fields[1], Serial: fields[2]}
+	if err := out.validate(); err != nil {
+		return SGTIN{}, err
+	}
+
+	return out, nil
+}
+
+// SGTINFromGTIN builds SGTIN from GTIN-14 and serial. Length of the GS1 Company Prefix is not encoded in GTIN and needs to be provided by the caller. If GTIN has invalid check digit an error is returned.
+func SGTINFromGTIN(gtin string, companyPrefixLength int, serial string) (SGTIN, error) {
+	if len(gtin) != 14 || !IsValidGTIN(gtin) {
+		return SGTIN{}, errors.New("invalid input (GTIN)")
+	}
+
+	if companyPrefixLength < 6 || companyPrefixLength > 12 {
+		return SGTIN{}, errors.New("invalid input (company prefix)")
+	}
+
+	out := SGTIN{
+		CompanyPrefix: gtin[1 : 1+companyPrefixLength],
+		ItemReference: gtin[:1] + gtin[1+companyPrefixLength:13],
+		Serial:        serial,
+	}
+	if err := out.validate(); err != nil {
+		return SGTIN{}, err
+	}
+
+	return out, nil
+}
+
+// GTIN returns GTIN-14 including check digit.
+func (s SGTIN) GTIN() string {
+	digits := s.ItemReference[:1] + s.CompanyPrefix + s.ItemReference[1:]
+
+	return digits + string(GTINCheckDigit(digits))
+}
+
+// String returns SGTIN pure identity URI.
+func (s SGTIN) String() string {
+	return EpcIdPrefix + "sgtin:" + s.CompanyPrefix + "." + s.ItemReference + "." + escape(s.Serial)
+}
+
+// ToUrnDev maps SGTIN into urn:dev:ops identifier. GS1 Company Prefix is not an IANA private enterprise number so the organization is given by the caller. Product is formed from company prefix and item reference separated by "." (e.g. "0614141.812345"), which keeps leading zeros and stays free of dashes. Serial is used as is and serials with characters outside urn:dev identifier syntax cannot be mapped.
+func (s SGTIN) ToUrnDev(pen uint32) (rfc9039.UrnDev, error) {
+	if err := s.validate(); err != nil {
+		return rfc9039.UrnDev{}, err
+	}
+
+	if match, _ := regexp.MatchString(rfc9039.DevUrnReservedRegEx, s.Serial); !match {
+		return rfc9039.UrnDev{}, errors.New("invalid input (serial)")
+	}
+
+	return rfc9039.Parse(fmt.Sprintf("%sops:%d-%s.%s-%s", rfc9039.UrnDevPrefix, pen, s.CompanyPrefix, s.ItemReference, s.Serial))
+}
+
+// SGTINFromUrnDev is the inverse of SGTIN.ToUrnDev.
+func SGTINFromUrnDev(devUrn rfc9039.UrnDev) (SGTIN, error) {
+	if devUrn.Subtype != "ops" {
+		return SGTIN{}, errors.New("invalid input (not ops)")
+	}
+
+	product := strings.Split(devUrn.Product, ".")
+	if len(product) != 2 {
+		return SGTIN{}, errors.New("invalid input (product)")
+	}
+
+	out := SGTIN{CompanyPrefix: product[0], ItemReference: product[1], Serial: devUrn.Serial}
+	if err := out.validate(); err != nil {
+		return SGTIN{}, err
+	}
+
+	return out, nil
+}
+
+// ParseSGLN parses SGLN pure identity URI, for example "urn:epc:id:sgln:0614141.12345.400". If incorrectly formed URI is given as input an error is returned.
+func ParseSGLN(name string) (SGLN, error) {
+	fields, err := splitURI(name, "sgln", 3)
+	if err != nil {
+		return SGLN{}, err
+	}
+
+	out := SGLN{CompanyPrefix: fields[0], LocationReference: fields[1], Extension: fields[2]}
+
+	if !isValidCompanyPrefix(out.CompanyPrefix) {
+		return SGLN{}, errors.New("invalid input (company prefix)")
+	}
+
+	if len(out.CompanyPrefix)+len(out.LocationReference) != 12 || (out.LocationReference != "" && !isValidDigits(out.LocationReference)) {
+		return SGLN{}, errors.New("invalid input (location reference)")
+	}
+
+	if len(out.Extension) < 1 || len(out.Extension) > 20 || !isValidCs82(out.Extension) {
+		return SGLN{}, errors.New("invalid input (extension)")
+	}
+
+	return out, nil
+}
+
+// String returns SGLN pure identity URI.
+func (s SGLN) String() string {
+	return EpcIdPrefix + "sgln:" + s.CompanyPrefix + "." + s.LocationReference + "." + escape(s.Extension)
+}
+
+// ParseGIAI parses GIAI pure identity URI, for example "urn:epc:id:giai:0614141.12345400". If incorrectly formed URI is given as input an error is returned.
+func ParseGIAI(name string) (GIAI, error) {
+	fields, err := splitURI(name, "giai", 2)
+	if err != nil {
+		return GIAI{}, err
+	}
+
+	out := GIAI{CompanyPrefix: fields[0], AssetReference: fields[1]}
+
+	if !isValidCompanyPrefix(out.CompanyPrefix) {
+		return GIAI{}, errors.New("invalid input (company prefix)")
+	}
+
+	if len(out.AssetReference) < 1 || len(out.CompanyPrefix)+len(out.AssetReference) > 30 || !isValidCs82(out.AssetReference) {
+		return GIAI{}, errors.New("invalid input (asset reference)")
+	}
+
+	return out, nil
+}
+
+// String returns GIAI pure identity URI.
+func (g GIAI) String() string {
+	return EpcIdPrefix + "giai:" + g.CompanyPrefix + "." + escape(g.AssetReference)
+}
+
+// ParseGRAI parses GRAI pure identity URI, for example "urn:epc:id:grai:0614141.12345.400". If incorrectly formed URI is given as input an error is returned.
+func ParseGRAI(name string) (GRAI, error) {
+	fields, err := splitURI(name, "grai", 3)
+	if err != nil {
+		return GRAI{}, err
+	}
+
+	out := GRAI{CompanyPrefix: fields[0], AssetType: fields[1], Serial: fields[2]}
+
+	if !isValidCompanyPrefix(out.CompanyPrefix) {
+		return GRAI{}, errors.New("invalid input (company prefix)")
+	}
+
+	if len(out.CompanyPrefix)+len(out.AssetType) != 12 || (out.AssetType != "" && !isValidDigits(out.AssetType)) {
+		return GRAI{}, errors.New("invalid input (asset type)")
+	}
+
+	if len(out.Serial) > 16 || !isValidCs82(out.Serial) {
+		return GRAI{}, errors.New("invalid input (serial)")
+	}
+
+	return out, nil
+}
+
+// String returns GRAI pure identity URI.
+func (g GRAI) String() string {
+	return EpcIdPrefix + "grai:" + g.CompanyPrefix + "." + g.AssetType + "." + escape(g.Serial)
+}
+
+// Scheme defines binary tag encoding scheme.
+type Scheme int
+
+const (
+	SGTIN96 Scheme = iota
+	SGTIN198
+)
+
+const sgtin96Header = 0x30
+const sgtin198Header = 0x36
+
+// SGTINTag captures SGTIN decoded from binary tag encoding.
+type SGTINTag struct {
+	// Scheme is the binary encoding scheme.
+	Scheme Scheme
+	// Filter is the 3-bit filter value used for fast tag filtering, e.g. 1 for point of sale trade item.
+	Filter uint8
+	// SGTIN is the encoded pure identity.
+	SGTIN SGTIN
+}
+
+// sgtinPartitions lists company prefix and item reference bit lengths indexed by partition value, company prefix digit count is 12 - partition.
+var sgtinPartitions = [7][2]uint{{40, 4}, {37, 7}, {34, 10}, {30, 14}, {27, 17}, {24, 20}, {20, 24}}
+
+const sgtin96SerialBits = 38
+const sgtin198SerialChars = 20
+
+type bitReader struct {
+	data   []byte
+	offset uint
+}
+
+func (r *bitReader) read(bits uint) uint64 {
+	var out uint64
+	for i := uint(0); i < bits; i++ {
+		bit := (r.data[(r.offset+i)/8] >> (7 - (r.offset+i)%8)) & 1
+		out = out<<1 | uint64(bit)
+	}
+	r.offset += bits
+
+	return out
+}
+
+type bitWriter struct {
+	data   []byte
+	offset uint
+}
+
+func (w *bitWriter) write(value uint64, bits uint) {
+	for i := uint(0); i < bits; i++ {
+		if w.offset%8 == 0 {
+			w.data = append(w.data, 0)
+		}
+		bit := byte(value>>(bits-1-i)) & 1
+		w.data[w.offset/8] |= bit << (7 - w.offset%8)
+		w.offset++
+	}
+}
+
+// DecodeSGTINTag decodes SGTIN-96 (24 hex digits) or SGTIN-198 (50 hex digits, or 52 when padded to 16-bit word boundary) binary encoding from hex. If incorrectly formed encoding is given as input an error is returned.
+func DecodeSGTINTag(name string) (SGTINTag, error) {
+	data, err := hex.DecodeString(name)
+	if err != nil || len(data) == 0 {
+		return SGTINTag{}, errors.New("invalid input (hex)")
+	}
+
+	out := SGTINTag{}
+	var totalBits uint
+
+	switch {
+	case data[0] == sgtin96Header && len(data) == 12:
+		out.Scheme = SGTIN96
+		totalBits = 96
+	case data[0] == sgtin198Header && (len(data) == 25 || len(data) == 26):
+		out.Scheme = SGTIN198
+		totalBits = 198
+	default:
+		return SGTINTag{}, errors.New("invalid input (header)")
+	}
+
+	r := bitReader{data: data, offset: 8}
+	out.Filter = uint8(r.read(3))
+
+	partition := r.read(3)
+	if partition >= uint64(len(sgtinPartitions)) {
+		return SGTINTag{}, errors.New("invalid input (partition)")
+	}
+
+	cpBits, irBits := sgtinPartitions[partition][0], sgtinPartitions[partition][1]
+	cpDigits := 12 - int(partition)
+	irDigits := 13 - cpDigits
+
+	out.SGTIN.CompanyPrefix = fmt.Sprintf("%0*d", cpDigits, r.read(cpBits))
+	out.SGTIN.ItemReference = fmt.Sprintf("%0*d", irDigits, r.read(irBits))
+	if len(out.SGTIN.CompanyPrefix) != cpDigits || len(out.SGTIN.ItemReference) != irDigits {
+		return SGTINTag{}, errors.New("invalid input (company prefix or item reference)")
+	}
+
+	if out.Scheme == SGTIN96 {
+		out.SGTIN.Serial = strconv.FormatUint(r.read(sgtin96SerialBits), 10)
+	} else {
+		serial := make([]byte, sgtin198SerialChars)
+		for i := range serial {
+			serial[i] = byte(r.read(7))
+		}
+
+		// Serial is terminated by zero characters and nothing may follow them
+		out.SGTIN.Serial, _, _ = strings.Cut(string(serial), "\x00")
+		if strings.Trim(string(serial[len(out.SGTIN.Serial):]), "\x00") != "" {
+			return SGTINTag{}, errors.New("invalid input (serial)")
+		}
+	}
+
+	// Padding after the encoded bits needs to be zero
+	if r.read(uint(len(data))*8-totalBits) != 0 {
+		return SGTINTag{}, errors.New("invalid input (padding)")
+	}
+
+	if err := out.SGTIN.validate(); err != nil {
+		return SGTINTag{}, err
+	}
+
+	return out, nil
+}
+
+// Encode encodes SGTIN into binary tag encoding and returns it as upper case hex. SGTIN-96 is returned as 24 hex digits and SGTIN-198 as 52 hex digits padded to 16-bit word boundary as it is stored in tag memory. SGTIN-96 can only encode numeric serials below 2^38 without leading zeros.
+func (t SGTINTag) Encode() (string, error) {
+	if err := t.SGTIN.validate(); err != nil {
+		return "", err
+	}
+
+	if t.Filter > 7 {
+		return "", errors.New("invalid input (filter)")
+	}
+
+	partition := 12 - len(t.SGTIN.CompanyPrefix)
+	cpBits, irBits := sgtinPartitions[partition][0], sgtinPartitions[partition][1]
+	companyPrefix, _ := strconv.ParseUint(t.SGTIN.CompanyPrefix, 10, 64)
+	itemReference, _ := strconv.ParseUint(t.SGTIN.ItemReference, 10, 64)
+
+	w := bitWriter{}
+
+	switch t.Scheme {
+	case SGTIN96:
+		serial, err := strconv.ParseUint(t.SGTIN.Serial, 10, sgtin96SerialBits)
+		if err != nil || strconv.FormatUint(serial, 10) != t.SGTIN.Serial {
+			return "", errors.New("invalid input (serial not encodable in SGTIN-96)")
+		}
+
+		w.write(sgtin96Header, 8)
+		w.write(uint64(t.Filter), 3)
+		w.write(uint64(partition), 3)
+		w.write(companyPrefix, cpBits)
+		w.write(itemReference, irBits)
+		w.write(serial, sgtin96SerialBits)
+
+	case SGTIN198:
+		w.write(sgtin198Header, 8)
+		w.write(uint64(t.Filter), 3)
+		w.write(uint64(partition), 3)
+		w.write(companyPrefix, cpBits)
+		w.write(itemReference, irBits)
+		for i := 0; i < sgtin198SerialChars; i++ {
+			var c byte
+			if i < len(t.SGTIN.Serial) {
+				c = t.SGTIN.Serial[i]
+			}
+			w.write(uint64(c), 7)
+		}
+		// Pad to 16-bit word boundary
+		w.write(0, 208-198)
+
+	default:
+		return "", errors.New("invalid input (scheme)")
+	}
+
+	return strings.ToUpper(hex.EncodeToString(w.data)), nil
+}
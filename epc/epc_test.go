@@ -0,0 +1,243 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package epc
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func ExampleParseSGTIN() {
+	sgtin, _ := ParseSGTIN("urn:epc:id:sgtin:0614141.812345.6789")
+	fmt.Println(sgtin.GTIN())
+	tag, _ := SGTINTag{Scheme: SGTIN96, Filter: 3, SGTIN: sgtin}.Encode()
+	fmt.Println(tag)
+	// Output: 80614141123458
+	// 3074257BF7194E4000001A85
+}
+
+func ExampleSGTIN_ToUrnDev() {
+	sgtin, _ := ParseSGTIN("urn:epc:id:sgtin:0614141.812345.6789")
+	devUrn, _ := sgtin.ToUrnDev(32473)
+	fmt.Println(devUrn.FullName)
+	// Output: urn:dev:ops:32473-0614141.812345-6789
+}
+
+func TestParseSGTIN(t *testing.T) {
+	value, err := ParseSGTIN("urn:epc:id:sgtin:0614141.812345.6789")
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, SGTIN{CompanyPrefix: "0614141", ItemReference: "812345", Serial: "6789"}, value)
+	assert.Equal(t, "urn:epc:id:sgtin:0614141.812345.6789", value.String())
+}
+
+func TestParseSGTINEscaped(t *testing.T) {
+	value, err := ParseSGTIN("urn:epc:id:sgtin:0614141.712345.32a%2Fb")
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, "32a/b", value.Serial)
+	assert.Equal(t, "urn:epc:id:sgtin:0614141.712345.32a%2Fb", value.String())
+}
+
+func TestParseSGTINInvalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"urn:epc:id:sgln:0614141.812345.6789",
+		"urn:epc:id:sgtin:0614141.812345",
+		"urn:epc:id:sgtin:0614141.81234.6789",
+		"urn:epc:id:sgtin:06141.81234567.6789",
+		"urn:epc:id:sgtin:0614141.81234a.6789",
+		"urn:epc:id:sgtin:0614141.812345.",
+		"urn:epc:id:sgtin:0614141.812345.123456789012345678901",
+		"urn:epc:id:sgtin:0614141.812345.a%2",
+		"urn:epc:id:sgtin:0614141.812345.a%zz",
+		"urn:epc:id:sgtin:0614141.812345.a#b",
+	} {
+		value, err := ParseSGTIN(input)
+		assert.Error(t, err, input)
+		assert.Equal(t, SGTIN{}, value)
+	}
+}
+
+func TestGTIN(t *testing.T) {
+	assert.True(t, IsValidGTIN("80614141123458"))
+	assert.True(t, IsValidGTIN("4006381333931"))
+	assert.True(t, IsValidGTIN("036000291452"))
+	assert.True(t, IsValidGTIN("96385074"))
+	assert.False(t, IsValidGTIN("80614141123459"))
+	assert.False(t, IsValidGTIN("8061414112345"))
+	assert.False(t, IsValidGTIN("8061414112345a"))
+}
+
+func TestSGTINFromGTIN(t *testing.T) {
+	value, err := SGTINFromGTIN("80614141123458", 7, "6789")
+	if err != nil {
+		t.Fatalf("Failed to convert")
+		return
+	}
+	assert.Equal(t, SGTIN{CompanyPrefix: "0614141", ItemReference: "812345", Serial: "6789"}, value)
+
+	_, err = SGTINFromGTIN("80614141123459", 7, "6789")
+	assert.Error(t, err)
+
+	_, err = SGTINFromGTIN("80614141123458", 13, "6789")
+	assert.Error(t, err)
+}
+
+func TestParseSGLN(t *testing.T) {
+	value, err := ParseSGLN("urn:epc:id:sgln:0614141.12345.400")
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, SGLN{CompanyPrefix: "0614141", LocationReference: "12345", Extension: "400"}, value)
+	assert.Equal(t, "urn:epc:id:sgln:0614141.12345.400", value.String())
+
+	value, err = ParseSGLN("urn:epc:id:sgln:061414112345..0")
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, SGLN{CompanyPrefix: "061414112345", LocationReference: "", Extension: "0"}, value)
+
+	_, err = ParseSGLN("urn:epc:id:sgln:0614141.1234.400")
+	assert.Error(t, err)
+}
+
+func TestParseGIAI(t *testing.T) {
+	value, err := ParseGIAI("urn:epc:id:giai:0614141.12345400")
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, GIAI{CompanyPrefix: "0614141", AssetReference: "12345400"}, value)
+	assert.Equal(t, "urn:epc:id:giai:0614141.12345400", value.String())
+
+	_, err = ParseGIAI("urn:epc:id:giai:0614141.12345678901234567890123A")
+	assert.Error(t, err)
+
+	_, err = ParseGIAI("urn:epc:id:giai:0614141.")
+	assert.Error(t, err)
+}
+
+func TestParseGRAI(t *testing.T) {
+	value, err := ParseGRAI("urn:epc:id:grai:0614141.12345.400")
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, GRAI{CompanyPrefix: "0614141", AssetType: "12345", Serial: "400"}, value)
+	assert.Equal(t, "urn:epc:id:grai:0614141.12345.400", value.String())
+
+	_, err = ParseGRAI("urn:epc:id:grai:0614141.12345.12345678901234567")
+	assert.Error(t, err)
+}
+
+func TestDecodeSGTIN96(t *testing.T) {
+	value, err := DecodeSGTINTag("3074257BF7194E4000001A85")
+	if err != nil {
+		t.Fatalf("Failed to decode")
+		return
+	}
+	assert.Equal(t, SGTIN96, value.Scheme)
+	assert.Equal(t, uint8(3), value.Filter)
+	assert.Equal(t, SGTIN{CompanyPrefix: "0614141", ItemReference: "812345", Serial: "6789"}, value.SGTIN)
+}
+
+func TestSGTIN198RoundTrip(t *testing.T) {
+	tag := SGTINTag{Scheme: SGTIN198, Filter: 1, SGTIN: SGTIN{CompanyPrefix: "0614141", ItemReference: "712345", Serial: "32a/b"}}
+
+	encoded, err := tag.Encode()
+	if err != nil {
+		t.Fatalf("Failed to encode")
+		return
+	}
+	assert.Equal(t, "3634257BF6B7A659B2C2BF100000000000000000000000000000", encoded)
+
+	value, err := DecodeSGTINTag(encoded)
+	if err != nil {
+		t.Fatalf("Failed to decode")
+		return
+	}
+	assert.Equal(t, tag, value)
+
+	// Without padding to 16-bit word boundary
+	value, err = DecodeSGTINTag(encoded[:50])
+	if err != nil {
+		t.Fatalf("Failed to decode")
+		return
+	}
+	assert.Equal(t, tag, value)
+}
+
+func TestDecodeSGTINInvalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"3074257BF7194E4000001A8",
+		"3074257BF7194E4000001A8G",
+		"3174257BF7194E4000001A85",
+		// partition 7
+		"307C257BF7194E4000001A85",
+		// company prefix with too many digits for the partition
+		"3075FFFFFFFFFE4000001A85",
+		// character after terminating zero
+		"3634257BF6B7A659B2C2BF100000000000000000000000001000",
+		// non-zero padding
+		"3634257BF6B7A659B2C2BF100000000000000000000000000001",
+	} {
+		value, err := DecodeSGTINTag(input)
+		assert.Error(t, err, input)
+		assert.Equal(t, SGTINTag{}, value)
+	}
+}
+
+func TestEncodeSGTIN96Invalid(t *testing.T) {
+	sgtin := SGTIN{CompanyPrefix: "0614141", ItemReference: "812345", Serial: "0123"}
+
+	_, err := SGTINTag{Scheme: SGTIN96, SGTIN: sgtin}.Encode()
+	assert.Error(t, err)
+
+	sgtin.Serial = "274877906944"
+	_, err = SGTINTag{Scheme: SGTIN96, SGTIN: sgtin}.Encode()
+	assert.Error(t, err)
+
+	sgtin.Serial = "274877906943"
+	_, err = SGTINTag{Scheme: SGTIN96, SGTIN: sgtin}.Encode()
+	assert.NoError(t, err)
+
+	_, err = SGTINTag{Scheme: SGTIN96, Filter: 8, SGTIN: sgtin}.Encode()
+	assert.Error(t, err)
+}
+
+func TestSGTINToUrnDev(t *testing.T) {
+	sgtin := SGTIN{CompanyPrefix: "0614141", ItemReference: "812345", Serial: "AB.6789"}
+
+	value, err := sgtin.ToUrnDev(32473)
+	if err != nil {
+		t.Fatalf("Failed to convert")
+		return
+	}
+	assert.Equal(t, "urn:dev:ops:32473-0614141.812345-AB.6789", value.FullName)
+	assert.Equal(t, "32473", value.Organization)
+	assert.Equal(t, "0614141.812345", value.Product)
+	assert.Equal(t, "AB.6789", value.Serial)
+
+	back, err := SGTINFromUrnDev(value)
+	if err != nil {
+		t.Fatalf("Failed to convert")
+		return
+	}
+	assert.Equal(t, sgtin, back)
+}
+
+func TestSGTINToUrnDevInvalidSerial(t *testing.T) {
+	for _, serial := range []string{"A_B", "A:B", "32a/b"} {
+		_, err := SGTIN{CompanyPrefix: "0614141", ItemReference: "812345", Serial: serial}.ToUrnDev(32473)
+		assert.Error(t, err, serial)
+	}
+}
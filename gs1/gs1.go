@@ -0,0 +1,350 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package gs1 provides tools for parsing GS1 element strings and GS1 Digital Link URIs found on device labels.
+package gs1
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/epc"
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+// GroupSeparator is the ASCII GS character which barcode scanners transmit in place of FNC1 after variable length fields.
+const GroupSeparator = "\x1d"
+
+const AIRegEx = "^[0-9]{2,4}$"
+const DateRegEx = "^[0-9]{2}(0[1-9]|1[0-2])[0-9]{2}$"
+
+// Application identifiers with typed fields in Label.
+const (
+	AIGTIN           = "01"
+	AIBatch          = "10"
+	AIProductionDate = "11"
+	AIBestBefore     = "15"
+	AIExpirationDate = "17"
+	AISerial         = "21"
+)
+
+// Element is a single application identifier and its value.
+type Element struct {
+	// AI is the application identifier, e.g. "01".
+	AI string
+	// Value is the data field of the element.
+	Value string
+}
+
+// Label captures parsed GS1 element string or Digital Link. Commonly used device label fields are available as typed fields and all elements are kept in Elements.
+type Label struct {
+	// GTIN captures value of AI (01) as 14 digits.
+	GTIN string
+	// Serial captures value of AI (21).
+	Serial string
+	// Batch captures value of AI (10).
+	Batch string
+	// ProductionDate captures value of AI (11), zero if not present.
+	ProductionDate time.Time
+	// BestBefore captures value of AI (15), zero if not present.
+	BestBefore time.Time
+	// ExpirationDate captures value of AI (17), zero if not present.
+	ExpirationDate time.Time
+	// Elements captures all elements in the order they were given.
+	Elements []Element
+}
+
+// now is used as reference for resolving century of two digit years.
+var now = time.Now
+
+// aiLengths gives length of the application identifier based on its first two digits.
+var aiLengths = map[string]int{
+	"00": 2, "01": 2, "02": 2, "10": 2, "11": 2, "12": 2, "13": 2, "15": 2, "16": 2, "17": 2,
+	"20": 2, "21": 2, "22": 2, "23": 3, "24": 3, "25": 3, "30": 2, "31": 4, "32": 4, "33": 4,
+	"34": 4, "35": 4, "36": 4, "37": 2, "39": 4, "40": 3, "41": 3, "42": 3, "43": 4, "70": 4,
+	"71": 3, "72": 4, "80": 4, "81": 4, "82": 4, "90": 2, "91": 2, "92": 2, "93": 2, "94": 2,
+	"95": 2, "96": 2, "97": 2, "98": 2, "99": 2,
+}
+
+// predefinedLengths gives total length of application identifier and data for elements that are never followed by a separator, based on the first two digits of the application identifier.
+var predefinedLengths = map[string]int{
+	"00": 20, "01": 16, "02": 16, "03": 16, "04": 18, "11": 8, "12": 8, "13": 8, "14": 8, "15": 8,
+	"16": 8, "17": 8, "18": 8, "19": 8, "20": 4, "31": 10, "32": 10, "33": 10, "34": 10, "35": 10,
+	"36": 10, "41": 16,
+}
+
+// symbologyIdentifiers lists symbology identifier prefixes of GS1 carriers transmitted by scanners.
+var symbologyIdentifiers = []string{"]d2", "]C1", "]Q3", "]e0", "]J1"}
+
+func isValidAI(name string) bool {
+	match, _ := regexp.MatchString(AIRegEx, name)
+
+	return match && aiLengths[name[:2]] == len(name)
+}
+
+// ParseElementString parses GS1 element string either in human readable form "(01)09506000134352(21)ABC123" or as transmitted by a scanner where variable length fields are terminated by GS (FNC1) and the string may start with a symbology identifier such as "]d2". If incorrectly formed element string is given as input an error is returned.
+func ParseElementString(name string) (Label, error) {
+	var elements []Element
+	var err error
+
+	if strings.HasPrefix(name, "(") {
+		elements, err = splitBracketed(name)
+	} else {
+		elements, err = splitRaw(name)
+	}
+	if err != nil {
+		return Label{}, err
+	}
+
+	return newLabel(elements)
+}
+
+func splitBracketed(name string) ([]Element, error) {
+	var out []Element
+
+	for len(name) > 0 {
+		if name[0] != '(' {
+			return nil, errors.New("invalid input (element string)")
+		}
+
+		end := strings.IndexByte(name, ')')
+		if end < 0 {
+			return nil, errors.New("invalid input (element string)")
+		}
+
+		ai := name[1:end]
+		name = name[end+1:]
+
+		next := strings.IndexByte(name, '(')
+		if next < 0 {
+			next = len(name)
+		}
+
+		out = append(out, Element{AI: ai, Value: name[:next]})
+		name = name[next:]
+	}
+
+	return out, nil
+}
+
+func splitRaw(name string) ([]Element, error) {
+	for _, symbology := range symbologyIdentifiers {
+		if strings.HasPrefix(name, symbology) {
+			name = name[len(symbology):]
+			break
+		}
+	}
+
+	// Leading FNC1 may be transmitted as GS as well
+	name = strings.TrimPrefix(name, GroupSeparator)
+
+	var out []Element
+
+	for len(name) > 0 {
+		if len(name) < 2 {
+			return nil, errors.New("invalid input (element string)")
+		}
+
+		aiLen, ok := aiLengths[name[:2]]
+		if !ok || len(name) < aiLen {
+			return nil, errors.New("invalid input (AI)")
+		}
+		ai := name[:aiLen]
+
+		var end int
+		if total, ok := predefinedLengths[name[:2]]; ok {
+			if len(name) < total {
+				return nil, errors.New("invalid input (AI " + ai + " length)")
+			}
+			end = total
+		} else {
+			end = strings.Index(name, GroupSeparator)
+			if end < 0 {
+				end = len(name)
+			}
+		}
+
+		out = append(out, Element{AI: ai, Value: name[aiLen:end]})
+
+		name = strings.TrimPrefix(name[end:], GroupSeparator)
+	}
+
+	return out, nil
+}
+
+// parseDate parses YYMMDD date field. Day 00 means last day of the month and century is resolved as specified in GS1 General Specifications 7.12.
+func parseDate(name string) (time.Time, error) {
+	if match, _ := regexp.MatchString(DateRegEx, name); !match {
+		return time.Time{}, errors.New("invalid input (date)")
+	}
+
+	yy := int(name[0]-'0')*10 + int(name[1]-'0')
+	month := time.Month(int(name[2]-'0')*10 + int(name[3]-'0'))
+	day := int(name[4]-'0')*10 + int(name[5]-'0')
+
+	current := now().Year()
+	year := current - current%100 + yy
+	switch diff := yy - current%100; {
+	case diff >= 51:
+		year -= 100
+	case diff <= -50:
+		year += 100
+	}
+
+	if day == 0 {
+		// Day 0 of the next month is the last day of this month
+		return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC), nil
+	}
+
+	out := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if out.Day() != day {
+		return time.Time{}, errors.New("invalid input (date)")
+	}
+
+	return out, nil
+}
+
+func isValidCs82(name string, maxLen int) bool {
+	match, _ := regexp.MatchString(epc.Cs82RegEx, name)
+
+	return match && len(name) >= 1 && len(name) <= maxLen
+}
+
+func newLabel(elements []Element) (Label, error) {
+	if len(elements) == 0 {
+		return Label{}, errors.New("invalid input (no elements)")
+	}
+
+	out := Label{Elements: elements}
+
+	var err error
+	for _, element := range elements {
+		if !isValidAI(element.AI) {
+			return Label{}, errors.New("invalid input (AI " + element.AI + ")")
+		}
+
+		switch element.AI {
+		case AIGTIN:
+			if len(element.Value) != 14 || !epc.IsValidGTIN(element.Value) {
+				return Label{}, errors.New("invalid input (GTIN)")
+			}
+			out.GTIN = element.Value
+
+		case AISerial:
+			if !isValidCs82(element.Value, 20) {
+				return Label{}, errors.New("invalid input (serial)")
+			}
+			out.Serial = element.Value
+
+		case AIBatch:
+			if !isValidCs82(element.Value, 20) {
+				return Label{}, errors.New("invalid input (batch)")
+			}
+			out.Batch = element.Value
+
+		case AIProductionDate:
+			out.ProductionDate, err = parseDate(element.Value)
+
+		case AIBestBefore:
+			out.BestBefore, err = parseDate(element.Value)
+
+		case AIExpirationDate:
+			out.ExpirationDate, err = parseDate(element.Value)
+		}
+
+		if err != nil {
+			return Label{}, err
+		}
+	}
+
+	return out, nil
+}
+
+// digitalLinkPrimaryKeys lists application identifiers that can start the identifier path of a Digital Link URI.
+var digitalLinkPrimaryKeys = []string{"00", "01", "253", "255", "401", "402", "414", "417", "8003", "8004", "8006", "8010", "8013", "8017", "8018"}
+
+// ParseDigitalLink parses GS1 Digital Link URI such as "https://id.gs1.org/01/09506000134352/21/ABC123?11=231015". Any domain and custom path prefix before the primary key is accepted. GTIN given with 8, 12 or 13 digits is padded to 14 digits. Query parameters that are not application identifiers, such as linkType, are ignored. If incorrectly formed URI is given as input an error is returned.
+func ParseDigitalLink(name string) (Label, error) {
+	link, err := url.Parse(name)
+	if err != nil || (link.Scheme != "http" && link.Scheme != "https") {
+		return Label{}, errors.New("invalid input (URI)")
+	}
+
+	segments := strings.Split(strings.Trim(link.EscapedPath(), "/"), "/")
+
+	start := -1
+	for i := 0; i < len(segments) && start < 0; i++ {
+		for _, key := range digitalLinkPrimaryKeys {
+			if segments[i] == key && (len(segments)-i)%2 == 0 {
+				start = i
+				break
+			}
+		}
+	}
+	if start < 0 {
+		return Label{}, errors.New("invalid input (primary key)")
+	}
+
+	var elements []Element
+	for i := start; i < len(segments); i += 2 {
+		value, err := url.PathUnescape(segments[i+1])
+		if err != nil {
+			return Label{}, errors.New("invalid input (URI)")
+		}
+
+		if segments[i] == AIGTIN && (len(value) == 8 || len(value) == 12 || len(value) == 13) {
+			value = strings.Repeat("0", 14-len(value)) + value
+		}
+
+		elements = append(elements, Element{AI: segments[i], Value: value})
+	}
+
+	// Query keys are sorted by url.Values so walk the raw query to keep the order
+	for _, pair := range strings.Split(link.RawQuery, "&") {
+		key, value, _ := strings.Cut(pair, "=")
+		if !isValidAI(key) {
+			continue
+		}
+
+		value, err = url.QueryUnescape(value)
+		if err != nil {
+			return Label{}, errors.New("invalid input (URI)")
+		}
+
+		elements = append(elements, Element{AI: key, Value: value})
+	}
+
+	return newLabel(elements)
+}
+
+// Get returns value of the first element with given application identifier.
+func (l Label) Get(ai string) (string, bool) {
+	for _, element := range l.Elements {
+		if element.AI == ai {
+			return element.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// SGTIN combines GTIN and serial into SGTIN. Length of the GS1 Company Prefix is not encoded in GTIN and needs to be provided by the caller.
+func (l Label) SGTIN(companyPrefixLength int) (epc.SGTIN, error) {
+	if l.GTIN == "" || l.Serial == "" {
+		return epc.SGTIN{}, errors.New("invalid input (GTIN and serial required)")
+	}
+
+	return epc.SGTINFromGTIN(l.GTIN, companyPrefixLength, l.Serial)
+}
+
+// ToUrnDev maps GTIN and serial into urn:dev:ops identifier as specified by epc.SGTIN.ToUrnDev.
+func (l Label) ToUrnDev(companyPrefixLength int, pen uint32) (rfc9039.UrnDev, error) {
+	sgtin, err := l.SGTIN(companyPrefixLength)
+	if err != nil {
+		return rfc9039.UrnDev{}, err
+	}
+
+	return sgtin.ToUrnDev(pen)
+}
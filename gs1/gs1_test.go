@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package gs1
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func ExampleParseElementString() {
+	label, _ := ParseElementString("]d2010950600013435211231015" + "21ABC123" + GroupSeparator + "10LOT7")
+	fmt.Println(label.GTIN)
+	fmt.Println(label.Serial)
+	fmt.Println(label.Batch)
+	fmt.Println(label.ProductionDate.Format(time.DateOnly))
+	// Output: 09506000134352
+	// ABC123
+	// LOT7
+	// 2023-10-15
+}
+
+func ExampleParseDigitalLink() {
+	label, _ := ParseDigitalLink("https://id.gs1.org/01/09506000134352/21/ABC123")
+	devUrn, _ := label.ToUrnDev(7, 32473)
+	fmt.Println(devUrn.FullName)
+	// Output: urn:dev:ops:32473-9506000.013435-ABC123
+}
+
+func TestParseElementStringBracketed(t *testing.T) {
+	value, err := ParseElementString("(01)09506000134352(17)250600(10)A1/B(21)12345")
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, "09506000134352", value.GTIN)
+	assert.Equal(t, "12345", value.Serial)
+	assert.Equal(t, "A1/B", value.Batch)
+	assert.Equal(t, time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), value.ExpirationDate)
+	assert.True(t, value.ProductionDate.IsZero())
+	assert.Equal(t, []Element{{"01", "09506000134352"}, {"17", "250600"}, {"10", "A1/B"}, {"21", "12345"}}, value.Elements)
+}
+
+func TestParseElementStringRaw(t *testing.T) {
+	value, err := ParseElementString(GroupSeparator + "0109506000134352" + "10ABC" + GroupSeparator + "3103000189" + "15991231" + "21XYZ")
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, "09506000134352", value.GTIN)
+	assert.Equal(t, "ABC", value.Batch)
+	assert.Equal(t, "XYZ", value.Serial)
+	assert.Equal(t, 1999, value.BestBefore.Year())
+
+	weight, ok := value.Get("3103")
+	assert.True(t, ok)
+	assert.Equal(t, "000189", weight)
+
+	_, ok = value.Get("11")
+	assert.False(t, ok)
+}
+
+func TestParseElementStringInvalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"(01)09506000134353",
+		"(01)0950600013435",
+		"(01)09506000134352(21)",
+		"(01)09506000134352(11)231315",
+		"(01)09506000134352(11)230231",
+		"(01)09506000134352(1)1",
+		"(01)09506000134352(21)ABC#",
+		"(01)09506000134352(21",
+		"010950600013435",
+		"0109506000134352" + "11" + "2310",
+		"0109506000134352" + "0",
+		"]d2" + "5509506000134352",
+	} {
+		value, err := ParseElementString(input)
+		assert.Error(t, err, input)
+		assert.Equal(t, Label{}, value)
+	}
+}
+
+func TestParseDateCentury(t *testing.T) {
+	defer func() { now = time.Now }()
+	now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	value, err := parseDate("760101")
+	assert.NoError(t, err)
+	assert.Equal(t, 2076, value.Year())
+
+	value, err = parseDate("770101")
+	assert.NoError(t, err)
+	assert.Equal(t, 1977, value.Year())
+
+	now = func() time.Time { return time.Date(2090, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	value, err = parseDate("400101")
+	assert.NoError(t, err)
+	assert.Equal(t, 2140, value.Year())
+
+	value, err = parseDate("240200")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2124, 2, 29, 0, 0, 0, 0, time.UTC), value)
+}
+
+func TestParseDigitalLink(t *testing.T) {
+	value, err := ParseDigitalLink("https://example.com/products/01/9506000134352/10/AB%2F12/21/XYZ?17=250101&linkType=gs1:pip&3103=000189")
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, "09506000134352", value.GTIN)
+	assert.Equal(t, "AB/12", value.Batch)
+	assert.Equal(t, "XYZ", value.Serial)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), value.ExpirationDate)
+	assert.Equal(t, []Element{{"01", "09506000134352"}, {"10", "AB/12"}, {"21", "XYZ"}, {"17", "250101"}, {"3103", "000189"}}, value.Elements)
+}
+
+func TestParseDigitalLinkInvalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"ftp://id.gs1.org/01/09506000134352",
+		"https://id.gs1.org/",
+		"https://id.gs1.org/01/09506000134353",
+		"https://id.gs1.org/01/09506000134352/21",
+		"https://id.gs1.org/01/09506000134352/21/ABC%zz",
+		"https://id.gs1.org/01/09506000134352?17=991399",
+	} {
+		value, err := ParseDigitalLink(input)
+		assert.Error(t, err, input)
+		assert.Equal(t, Label{}, value)
+	}
+}
+
+func TestLabelToUrnDev(t *testing.T) {
+	label, _ := ParseElementString("(01)80614141123458(21)6789")
+
+	sgtin, err := label.SGTIN(7)
+	if err != nil {
+		t.Fatalf("Failed to convert")
+		return
+	}
+	assert.Equal(t, "urn:epc:id:sgtin:0614141.812345.6789", sgtin.String())
+
+	value, err := label.ToUrnDev(7, 32473)
+	if err != nil {
+		t.Fatalf("Failed to convert")
+		return
+	}
+	assert.Equal(t, "urn:dev:ops:32473-0614141.812345-6789", value.FullName)
+}
+
+func TestLabelToUrnDevMissingSerial(t *testing.T) {
+	label, _ := ParseElementString("(01)80614141123458")
+
+	_, err := label.ToUrnDev(7, 32473)
+	assert.Error(t, err)
+}
@@ -0,0 +1,353 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package cbor provides minimal RFC 8949 CBOR encoding and decoding for the data items used by device identifier formats.
+package cbor
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// Major types
+const (
+	MajorUnsigned = 0
+	MajorNegative = 1
+	MajorBytes    = 2
+	MajorText     = 3
+	MajorArray    = 4
+	MajorMap      = 5
+	MajorTag      = 6
+	MajorSimple   = 7
+)
+
+// TagURI is the tag number of URI text strings.
+const TagURI = 32
+
+// maxDepth limits nesting of arrays, maps and tags.
+const maxDepth = 32
+
+// Tag captures tagged data item.
+type Tag struct {
+	// Number is the tag number.
+	Number uint64
+	// Content is the decoded tag content.
+	Content any
+}
+
+// AppendHead appends initial byte and argument of data item in preferred serialization, i.e. using the shortest form for the argument.
+func AppendHead(dst []byte, major byte, arg uint64) []byte {
+	major <<= 5
+
+	switch {
+	case arg < 24:
+		return append(dst, major|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(dst, major|24, byte(arg))
+	case arg <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, major|25), uint16(arg))
+	case arg <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, major|26), uint32(arg))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, major|27), arg)
+	}
+}
+
+// AppendUint appends unsigned integer.
+func AppendUint(dst []byte, value uint64) []byte {
+	return AppendHead(dst, MajorUnsigned, value)
+}
+
+// AppendInt appends signed integer.
+func AppendInt(dst []byte, value int64) []byte {
+	if value < 0 {
+		return AppendHead(dst, MajorNegative, uint64(-1-value))
+	}
+
+	return AppendHead(dst, MajorUnsigned, uint64(value))
+}
+
+// AppendBytes appends byte string.
+func AppendBytes(dst []byte, value []byte) []byte {
+	return append(AppendHead(dst, MajorBytes, uint64(len(value))), value...)
+}
+
+// AppendText appends text string.
+func AppendText(dst []byte, value string) []byte {
+	return append(AppendHead(dst, MajorText, uint64(len(value))), value...)
+}
+
+// AppendArray appends header of array with given number of items. Items need to be appended after the header.
+func AppendArray(dst []byte, length int) []byte {
+	return AppendHead(dst, MajorArray, uint64(length))
+}
+
+// AppendTag appends tag number. Tag content needs to be appended after the tag number.
+func AppendTag(dst []byte, number uint64) []byte {
+	return AppendHead(dst, MajorTag, number)
+}
+
+// Decode decodes single CBOR data item. Integers are returned as int64, byte strings as []byte, text strings as string, arrays as []any, maps as map[any]any, floating point numbers as float64, tags as Tag and true, false, null and undefined as bool and nil. An error is returned for malformed input, trailing bytes, integers outside int64 range and map keys which are not integers or text strings.
+func Decode(data []byte) (any, error) {
+	d := decoder{data: data}
+
+	out, err := d.item(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := out.(breakMarker); ok {
+		return nil, errors.New("invalid input (unexpected break)")
+	}
+
+	if d.pos != len(d.data) {
+		return nil, errors.New("invalid input (trailing bytes)")
+	}
+
+	return out, nil
+}
+
+type decoder struct {
+	data []byte
+	pos  int
+}
+
+// breakMarker is returned by item when it encounters a "break" stop code.
+type breakMarker struct{}
+
+// length converts string length argument to int, lengths which cannot fit are reported as -1 and rejected by next.
+func length(arg uint64) int {
+	if arg > math.MaxInt32 {
+		return -1
+	}
+
+	return int(arg)
+}
+
+func (d *decoder) next(n int) ([]byte, error) {
+	if n < 0 || n > len(d.data)-d.pos {
+		return nil, errors.New("invalid input (truncated)")
+	}
+
+	out := d.data[d.pos : d.pos+n]
+	d.pos += n
+
+	return out, nil
+}
+
+// head reads initial byte and its argument. Indefinite length is reported with additional information 31.
+func (d *decoder) head() (major byte, info byte, arg uint64, err error) {
+	b, err := d.next(1)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	major, info = b[0]>>5, b[0]&0x1f
+
+	switch {
+	case info < 24:
+		arg = uint64(info)
+	case info == 24:
+		b, err = d.next(1)
+		if err == nil {
+			arg = uint64(b[0])
+		}
+	case info == 25:
+		b, err = d.next(2)
+		if err == nil {
+			arg = uint64(binary.BigEndian.Uint16(b))
+		}
+	case info == 26:
+		b, err = d.next(4)
+		if err == nil {
+			arg = uint64(binary.BigEndian.Uint32(b))
+		}
+	case info == 27:
+		b, err = d.next(8)
+		if err == nil {
+			arg = binary.BigEndian.Uint64(b)
+		}
+	case info == 31:
+		if major == MajorUnsigned || major == MajorNegative || major == MajorTag {
+			err = errors.New("invalid input (indefinite length)")
+		}
+	default:
+		err = errors.New("invalid input (reserved additional information)")
+	}
+
+	return major, info, arg, err
+}
+
+func (d *decoder) item(depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, errors.New("invalid input (nesting too deep)")
+	}
+
+	major, info, arg, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+
+	indefinite := info == 31
+
+	switch major {
+	case MajorUnsigned:
+		if arg > math.MaxInt64 {
+			return nil, errors.New("invalid input (integer out of range)")
+		}
+		return int64(arg), nil
+
+	case MajorNegative:
+		if arg > math.MaxInt64 {
+			return nil, errors.New("invalid input (integer out of range)")
+		}
+		return -1 - int64(arg), nil
+
+	case MajorBytes, MajorText:
+		var out []byte
+		if indefinite {
+			out, err = d.chunks(major)
+		} else {
+			out, err = d.next(length(arg))
+			out = append([]byte{}, out...)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if major == MajorText {
+			return string(out), nil
+		}
+		return out, nil
+
+	case MajorArray:
+		out := []any{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			value, err := d.item(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := value.(breakMarker); ok {
+				if !indefinite {
+					return nil, errors.New("invalid input (unexpected break)")
+				}
+				break
+			}
+			out = append(out, value)
+		}
+		return out, nil
+
+	case MajorMap:
+		out := map[any]any{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			key, err := d.item(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := key.(breakMarker); ok {
+				if !indefinite {
+					return nil, errors.New("invalid input (unexpected break)")
+				}
+				break
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, errors.New("invalid input (map key type)")
+			}
+			value, err := d.item(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := value.(breakMarker); ok {
+				return nil, errors.New("invalid input (unexpected break)")
+			}
+			if _, ok := out[key]; ok {
+				return nil, errors.New("invalid input (duplicate map key)")
+			}
+			out[key] = value
+		}
+		return out, nil
+
+	case MajorTag:
+		content, err := d.item(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := content.(breakMarker); ok {
+			return nil, errors.New("invalid input (unexpected break)")
+		}
+		return Tag{Number: arg, Content: content}, nil
+
+	default:
+		switch info {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22, 23:
+			return nil, nil
+		case 25:
+			return halfToFloat64(uint16(arg)), nil
+		case 26:
+			return float64(math.Float32frombits(uint32(arg))), nil
+		case 27:
+			return math.Float64frombits(arg), nil
+		case 31:
+			return breakMarker{}, nil
+		default:
+			return nil, errors.New("invalid input (unsupported simple value)")
+		}
+	}
+}
+
+// chunks reads indefinite length string chunks until break.
+func (d *decoder) chunks(major byte) ([]byte, error) {
+	out := []byte{}
+
+	for {
+		chunkMajor, info, arg, err := d.head()
+		if err != nil {
+			return nil, err
+		}
+
+		if chunkMajor == MajorSimple && info == 31 {
+			return out, nil
+		}
+
+		if chunkMajor != major || info == 31 {
+			return nil, errors.New("invalid input (indefinite length chunk)")
+		}
+
+		chunk, err := d.next(length(arg))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, chunk...)
+	}
+}
+
+// halfToFloat64 converts IEEE 754 half precision number as shown in RFC 8949 Appendix D.
+func halfToFloat64(half uint16) float64 {
+	exp := int(half>>10) & 0x1f
+	mant := float64(half & 0x3ff)
+
+	var val float64
+	switch exp {
+	case 0:
+		val = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			val = math.Inf(1)
+		} else {
+			val = math.NaN()
+		}
+	default:
+		val = math.Ldexp(mant+1024, exp-25)
+	}
+
+	if half&0x8000 != 0 {
+		return -val
+	}
+
+	return val
+}
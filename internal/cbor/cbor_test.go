@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cbor
+
+import (
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func decodeHex(t *testing.T, input string) (any, error) {
+	data, err := hex.DecodeString(input)
+	if err != nil {
+		t.Fatalf("Failed to decode hex")
+	}
+
+	return Decode(data)
+}
+
+// Test vectors from RFC 8949 Appendix A
+func TestDecode(t *testing.T) {
+	for _, test := range []struct {
+		input  string
+		output any
+	}{
+		{"00", int64(0)},
+		{"17", int64(23)},
+		{"1818", int64(24)},
+		{"1903e8", int64(1000)},
+		{"1a000f4240", int64(1000000)},
+		{"1b000000e8d4a51000", int64(1000000000000)},
+		{"20", int64(-1)},
+		{"3863", int64(-100)},
+		{"f90000", 0.0},
+		{"f93c00", 1.0},
+		{"f93e00", 1.5},
+		{"f97bff", 65504.0},
+		{"f90001", 5.960464477539063e-8},
+		{"fa47c35000", 100000.0},
+		{"fb3ff199999999999a", 1.1},
+		{"fbc010666666666666", -4.1},
+		{"f97c00", math.Inf(1)},
+		{"f4", false},
+		{"f5", true},
+		{"f6", nil},
+		{"40", []byte{}},
+		{"4401020304", []byte{1, 2, 3, 4}},
+		{"60", ""},
+		{"6449455446", "IETF"},
+		{"62c3bc", "ü"},
+		{"83010203", []any{int64(1), int64(2), int64(3)}},
+		{"8301820203820405", []any{int64(1), []any{int64(2), int64(3)}, []any{int64(4), int64(5)}}},
+		{"a201020304", map[any]any{int64(1): int64(2), int64(3): int64(4)}},
+		{"a26161016162820203", map[any]any{"a": int64(1), "b": []any{int64(2), int64(3)}}},
+		{"c074323031332d30332d32315432303a30343a30305a", Tag{Number: 0, Content: "2013-03-21T20:04:00Z"}},
+		{"d82076687474703a2f2f7777772e6578616d706c652e636f6d", Tag{Number: 32, Content: "http://www.example.com"}},
+		{"5f42010243030405ff", []byte{1, 2, 3, 4, 5}},
+		{"7f657374726561646d696e67ff", "streaming"},
+		{"9f018202039f0405ffff", []any{int64(1), []any{int64(2), int64(3)}, []any{int64(4), int64(5)}}},
+		{"bf61610161629f0203ffff", map[any]any{"a": int64(1), "b": []any{int64(2), int64(3)}}},
+	} {
+		value, err := decodeHex(t, test.input)
+		if err != nil {
+			t.Fatalf("Failed to decode %s: %v", test.input, err)
+			return
+		}
+		assert.Equal(t, test.output, value, test.input)
+	}
+
+	value, err := decodeHex(t, "f97e00")
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(value.(float64)))
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"18",
+		"1b0000",
+		"1c",
+		"1f",
+		"3bffffffffffffffff",
+		"1bffffffffffffffff",
+		"62c3",
+		"830102",
+		"a10102a1",
+		"a1f601",
+		"a201020103",
+		"0000",
+		"ff",
+		"8301ff02",
+		"5f4101610aff",
+		"5f5f4101ffff",
+		"f818",
+		"c0",
+		"81818181818181818181818181818181818181818181818181818181818181818100",
+	} {
+		value, err := decodeHex(t, input)
+		assert.Error(t, err, input)
+		assert.Nil(t, value, input)
+	}
+}
+
+// Test vectors from RFC 8949 Appendix A
+func TestAppend(t *testing.T) {
+	for _, test := range []struct {
+		output string
+		input  []byte
+	}{
+		{"00", AppendUint(nil, 0)},
+		{"0a", AppendUint(nil, 10)},
+		{"17", AppendUint(nil, 23)},
+		{"1818", AppendUint(nil, 24)},
+		{"1864", AppendUint(nil, 100)},
+		{"1903e8", AppendUint(nil, 1000)},
+		{"1a000f4240", AppendUint(nil, 1000000)},
+		{"1b000000e8d4a51000", AppendUint(nil, 1000000000000)},
+		{"1bffffffffffffffff", AppendUint(nil, 18446744073709551615)},
+		{"20", AppendInt(nil, -1)},
+		{"29", AppendInt(nil, -10)},
+		{"3863", AppendInt(nil, -100)},
+		{"3903e7", AppendInt(nil, -1000)},
+		{"1903e8", AppendInt(nil, 1000)},
+		{"40", AppendBytes(nil, []byte{})},
+		{"4401020304", AppendBytes(nil, []byte{1, 2, 3, 4})},
+		{"60", AppendText(nil, "")},
+		{"6161", AppendText(nil, "a")},
+		{"6449455446", AppendText(nil, "IETF")},
+		{"62225c", AppendText(nil, "\"\\")},
+		{"80", AppendArray(nil, 0)},
+		{"83010203", AppendUint(AppendUint(AppendUint(AppendArray(nil, 3), 1), 2), 3)},
+		{"d82076687474703a2f2f7777772e6578616d706c652e636f6d", AppendText(AppendTag(nil, TagURI), "http://www.example.com")},
+	} {
+		assert.Equal(t, test.output, hex.EncodeToString(test.input))
+
+		if test.output == "1bffffffffffffffff" {
+			continue
+		}
+
+		// Round trip through decoder
+		value, err := decodeHex(t, test.output)
+		assert.NoError(t, err, test.output)
+		assert.NotNil(t, value, test.output)
+	}
+}
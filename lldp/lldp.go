@@ -0,0 +1,326 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package lldp provides tools for decoding IEEE 802.1AB LLDP Chassis ID and Port ID TLVs and mapping them into urn:dev identifiers.
+package lldp
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+// invalidCharactersRegEx matches runs of characters not valid in urn:dev identifiers.
+var invalidCharactersRegEx = regexp.MustCompile("[^A-Za-z0-9\\.\\-]+")
+
+// TLV types.
+const (
+	TLVEnd       = 0
+	TLVChassisID = 1
+	TLVPortID    = 2
+)
+
+// IANA address family numbers used in network address subtypes.
+const (
+	AddressFamilyIPv4 = 1
+	AddressFamilyIPv6 = 2
+)
+
+// ChassisIDSubtype defines the format of Chassis ID.
+type ChassisIDSubtype uint8
+
+const (
+	ChassisComponent       ChassisIDSubtype = 1
+	ChassisInterfaceAlias  ChassisIDSubtype = 2
+	ChassisPortComponent   ChassisIDSubtype = 3
+	ChassisMACAddress      ChassisIDSubtype = 4
+	ChassisNetworkAddress  ChassisIDSubtype = 5
+	ChassisInterfaceName   ChassisIDSubtype = 6
+	ChassisLocallyAssigned ChassisIDSubtype = 7
+)
+
+// PortIDSubtype defines the format of Port ID.
+type PortIDSubtype uint8
+
+const (
+	PortInterfaceAlias  PortIDSubtype = 1
+	PortComponent       PortIDSubtype = 2
+	PortMACAddress      PortIDSubtype = 3
+	PortNetworkAddress  PortIDSubtype = 4
+	PortInterfaceName   PortIDSubtype = 5
+	PortAgentCircuitID  PortIDSubtype = 6
+	PortLocallyAssigned PortIDSubtype = 7
+)
+
+// ChassisID captures decoded Chassis ID TLV.
+type ChassisID struct {
+	// Subtype is the format of Value.
+	Subtype ChassisIDSubtype
+	// Value is the chassis identifier. With network address subtype the first byte is the address family.
+	Value []byte
+}
+
+// PortID captures decoded Port ID TLV.
+type PortID struct {
+	// Subtype is the format of Value.
+	Subtype PortIDSubtype
+	// Value is the port identifier. With network address subtype the first byte is the address family.
+	Value []byte
+}
+
+// String returns name of the subtype as used in 802.1AB.
+func (s ChassisIDSubtype) String() string {
+	switch s {
+	case ChassisComponent:
+		return "chassis component"
+	case ChassisInterfaceAlias:
+		return "interface alias"
+	case ChassisPortComponent:
+		return "port component"
+	case ChassisMACAddress:
+		return "MAC address"
+	case ChassisNetworkAddress:
+		return "network address"
+	case ChassisInterfaceName:
+		return "interface name"
+	case ChassisLocallyAssigned:
+		return "locally assigned"
+	default:
+		return "reserved"
+	}
+}
+
+// String returns name of the subtype as used in 802.1AB.
+func (s PortIDSubtype) String() string {
+	switch s {
+	case PortInterfaceAlias:
+		return "interface alias"
+	case PortComponent:
+		return "port component"
+	case PortMACAddress:
+		return "MAC address"
+	case PortNetworkAddress:
+		return "network address"
+	case PortInterfaceName:
+		return "interface name"
+	case PortAgentCircuitID:
+		return "agent circuit ID"
+	case PortLocallyAssigned:
+		return "locally assigned"
+	default:
+		return "reserved"
+	}
+}
+
+// ParseTLV splits the first TLV from data, which holds 7-bit type and 9-bit length header followed by value, and returns the rest of data following it. If data is too short for the TLV an error is returned.
+func ParseTLV(data []byte) (uint8, []byte, []byte, error) {
+	if len(data) < 2 {
+		return 0, nil, nil, errors.New("invalid input (tlv header)")
+	}
+
+	header := binary.BigEndian.Uint16(data)
+	length := int(header & 0x01ff)
+	if len(data) < 2+length {
+		return 0, nil, nil, errors.New("invalid input (tlv length)")
+	}
+
+	return uint8(header >> 9), data[2 : 2+length], data[2+length:], nil
+}
+
+// checkValue validates identifier of MAC and network address subtypes, other identifiers can be any 1 to 255 bytes.
+func checkValue(value []byte, mac bool, network bool) error {
+	if len(value) < 1 || len(value) > 255 {
+		return errors.New("invalid input (id length)")
+	}
+
+	if mac && len(value) != 6 && len(value) != 8 {
+		return errors.New("invalid input (mac address)")
+	}
+
+	if network && ((value[0] == AddressFamilyIPv4 && len(value) != 5) || (value[0] == AddressFamilyIPv6 && len(value) != 17)) {
+		return errors.New("invalid input (network address)")
+	}
+
+	return nil
+}
+
+// ParseChassisID decodes Chassis ID TLV including the TLV header. MAC address has to be 6 byte EUI-48 or 8 byte EUI-64 and IPv4 and IPv6 network addresses have to be of the right length. If incorrectly formed TLV is given as input an error is returned.
+func ParseChassisID(tlv []byte) (ChassisID, error) {
+	tlvType, value, _, err := ParseTLV(tlv)
+	if err != nil {
+		return ChassisID{}, err
+	}
+
+	if tlvType != TLVChassisID || len(value) < 1 {
+		return ChassisID{}, errors.New("invalid input (chassis id tlv)")
+	}
+
+	subtype := ChassisIDSubtype(value[0])
+	if subtype < ChassisComponent || subtype > ChassisLocallyAssigned {
+		return ChassisID{}, errors.New("invalid input (chassis id subtype)")
+	}
+
+	if err := checkValue(value[1:], subtype == ChassisMACAddress, subtype == ChassisNetworkAddress); err != nil {
+		return ChassisID{}, err
+	}
+
+	return ChassisID{Subtype: subtype, Value: append([]byte{}, value[1:]...)}, nil
+}
+
+// ParsePortID decodes Port ID TLV including the TLV header, see ParseChassisID.
+func ParsePortID(tlv []byte) (PortID, error) {
+	tlvType, value, _, err := ParseTLV(tlv)
+	if err != nil {
+		return PortID{}, err
+	}
+
+	if tlvType != TLVPortID || len(value) < 1 {
+		return PortID{}, errors.New("invalid input (port id tlv)")
+	}
+
+	subtype := PortIDSubtype(value[0])
+	if subtype < PortInterfaceAlias || subtype > PortLocallyAssigned {
+		return PortID{}, errors.New("invalid input (port id subtype)")
+	}
+
+	if err := checkValue(value[1:], subtype == PortMACAddress, subtype == PortNetworkAddress); err != nil {
+		return PortID{}, err
+	}
+
+	return PortID{Subtype: subtype, Value: append([]byte{}, value[1:]...)}, nil
+}
+
+// ParseLLDPDU decodes Chassis ID and Port ID TLVs which are the first two TLVs of LLDP data unit, i.e. Ethernet frame payload.
+func ParseLLDPDU(data []byte) (ChassisID, PortID, error) {
+	_, _, rest, err := ParseTLV(data)
+	if err != nil {
+		return ChassisID{}, PortID{}, err
+	}
+
+	chassis, err := ParseChassisID(data)
+	if err != nil {
+		return ChassisID{}, PortID{}, err
+	}
+
+	port, err := ParsePortID(rest)
+	if err != nil {
+		return ChassisID{}, PortID{}, err
+	}
+
+	return chassis, port, nil
+}
+
+// formatAddress formats MAC address with ":" separated hex digits and IPv4 and IPv6 network addresses in their usual text forms. Other network addresses are shown as address family and hex digits and empty value as empty string.
+func formatAddress(value []byte, mac bool, network bool) string {
+	switch {
+	case len(value) == 0:
+		return ""
+	case mac:
+		parts := []string{}
+		for _, part := range value {
+			parts = append(parts, fmt.Sprintf("%02x", part))
+		}
+		return strings.Join(parts, ":")
+	case network && (value[0] == AddressFamilyIPv4 || value[0] == AddressFamilyIPv6):
+		return net.IP(value[1:]).String()
+	case network:
+		return fmt.Sprintf("%d:%s", value[0], hex.EncodeToString(value[1:]))
+	default:
+		return string(value)
+	}
+}
+
+// String returns chassis identifier in text form, e.g. "00:24:be:80:4f:f1" for MAC address and "192.0.2.1" for IPv4 network address. Other subtypes are text.
+func (c ChassisID) String() string {
+	return formatAddress(c.Value, c.Subtype == ChassisMACAddress, c.Subtype == ChassisNetworkAddress)
+}
+
+// String returns port identifier in text form, see ChassisID.String. Agent circuit ID is shown in hex.
+func (p PortID) String() string {
+	if p.Subtype == PortAgentCircuitID {
+		return hex.EncodeToString(p.Value)
+	}
+
+	return formatAddress(p.Value, p.Subtype == PortMACAddress, p.Subtype == PortNetworkAddress)
+}
+
+// eui64 converts MAC address into EUI-64 hex string by inserting "fffe" into EUI-48.
+func eui64(value []byte) string {
+	if len(value) == 6 {
+		return hex.EncodeToString(value[:3]) + "fffe" + hex.EncodeToString(value[3:])
+	}
+
+	return hex.EncodeToString(value)
+}
+
+// componentNames are the subtype prefixes of port components.
+var componentNames = map[PortIDSubtype]string{
+	PortInterfaceAlias:  "ifalias",
+	PortComponent:       "port",
+	PortMACAddress:      "mac",
+	PortNetworkAddress:  "addr",
+	PortInterfaceName:   "ifname",
+	PortAgentCircuitID:  "circuit",
+	PortLocallyAssigned: "local",
+}
+
+// Component returns port identifier as urn:dev identifier prefixed with subtype name and ".", so that ports of different subtypes do not map to the same component: hex digits for MAC address, IPv6 network address and agent circuit ID, IPv4 network address in dotted form and text subtypes with runs of characters not valid in urn:dev replaced with ".", e.g. "ifname.GigabitEthernet1.0.1" for interface name "GigabitEthernet1/0/1". If port identifier is not valid an error is returned.
+func (p PortID) Component() (string, error) {
+	name, ok := componentNames[p.Subtype]
+	if !ok {
+		return "", errors.New("invalid input (port id subtype)")
+	}
+
+	if err := checkValue(p.Value, p.Subtype == PortMACAddress, p.Subtype == PortNetworkAddress); err != nil {
+		return "", err
+	}
+
+	switch {
+	case p.Subtype == PortMACAddress || p.Subtype == PortAgentCircuitID:
+		return name + "." + hex.EncodeToString(p.Value), nil
+	case p.Subtype == PortNetworkAddress && p.Value[0] == AddressFamilyIPv4:
+		return name + "." + net.IP(p.Value[1:]).String(), nil
+	case p.Subtype == PortNetworkAddress:
+		return name + "." + hex.EncodeToString(p.Value), nil
+	}
+
+	out := strings.Trim(invalidCharactersRegEx.ReplaceAllString(string(p.Value), "."), ".")
+	if out == "" {
+		return name + "." + hex.EncodeToString(p.Value), nil
+	}
+
+	return name + "." + out, nil
+}
+
+// ToUrnDev maps chassis with MAC address subtype into urn:dev:mac identifier, converting EUI-48 into EUI-64 by inserting "fffe". Other subtypes do not identify the device globally and an error is returned.
+func (c ChassisID) ToUrnDev() (rfc9039.UrnDev, error) {
+	if c.Subtype != ChassisMACAddress {
+		return rfc9039.UrnDev{}, errors.New("invalid input (chassis id subtype " + c.Subtype.String() + ")")
+	}
+
+	if err := checkValue(c.Value, true, false); err != nil {
+		return rfc9039.UrnDev{}, err
+	}
+
+	return rfc9039.Parse(rfc9039.UrnDevPrefix + "mac:" + eui64(c.Value))
+}
+
+// ToUrnDev maps port into urn:dev identifier of the chassis with port as component, for example "urn:dev:mac:0024befffe804ff1_ifname.GigabitEthernet1.0.1", see ChassisID.ToUrnDev and PortID.Component.
+func ToUrnDev(chassis ChassisID, port PortID) (rfc9039.UrnDev, error) {
+	devUrn, err := chassis.ToUrnDev()
+	if err != nil {
+		return rfc9039.UrnDev{}, err
+	}
+
+	component, err := port.Component()
+	if err != nil {
+		return rfc9039.UrnDev{}, err
+	}
+
+	return rfc9039.Parse(devUrn.FullName + "_" + component)
+}
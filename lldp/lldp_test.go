@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package lldp
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// tlv builds TLV of given type from subtype and value.
+func tlv(tlvType uint8, subtype uint8, value []byte) []byte {
+	length := len(value) + 1
+
+	return append([]byte{tlvType<<1 | byte(length>>8), byte(length), subtype}, value...)
+}
+
+func decodeHex(text string) []byte {
+	data, _ := hex.DecodeString(text)
+
+	return data
+}
+
+func ExampleParseLLDPDU() {
+	frame := append(decodeHex("020704"+"0024be804ff1"), tlv(TLVPortID, uint8(PortInterfaceName), []byte("GigabitEthernet1/0/1"))...)
+	frame = append(frame, decodeHex("06020078"+"0000")...)
+
+	chassis, port, _ := ParseLLDPDU(frame)
+	fmt.Println(chassis.Subtype, chassis)
+	fmt.Println(port.Subtype, port)
+
+	devUrn, _ := ToUrnDev(chassis, port)
+	fmt.Println(devUrn.FullName)
+	// Output: MAC address 00:24:be:80:4f:f1
+	// interface name GigabitEthernet1/0/1
+	// urn:dev:mac:0024befffe804ff1_ifname.GigabitEthernet1.0.1
+}
+
+func TestParseChassisID(t *testing.T) {
+	for _, test := range []struct {
+		subtype ChassisIDSubtype
+		value   []byte
+		text    string
+		urn     string
+	}{
+		{ChassisComponent, []byte("FOC1234X0AB"), "FOC1234X0AB", ""},
+		{ChassisInterfaceAlias, []byte("uplink"), "uplink", ""},
+		{ChassisPortComponent, []byte("slot 1"), "slot 1", ""},
+		{ChassisMACAddress, decodeHex("0024be804ff1"), "00:24:be:80:4f:f1", "urn:dev:mac:0024befffe804ff1"},
+		{ChassisMACAddress, decodeHex("0024befffe804ff1"), "00:24:be:ff:fe:80:4f:f1", "urn:dev:mac:0024befffe804ff1"},
+		{ChassisNetworkAddress, decodeHex("01c0000201"), "192.0.2.1", ""},
+		{ChassisNetworkAddress, decodeHex("0220010db8000000000000000000000001"), "2001:db8::1", ""},
+		{ChassisNetworkAddress, decodeHex("060024be804ff1"), "6:0024be804ff1", ""},
+		{ChassisInterfaceName, []byte("eth0"), "eth0", ""},
+		{ChassisLocallyAssigned, []byte("switch-7"), "switch-7", ""},
+	} {
+		chassis, err := ParseChassisID(tlv(TLVChassisID, uint8(test.subtype), test.value))
+		if err != nil {
+			t.Fatalf("Failed to parse %s", test.text)
+			return
+		}
+		assert.Equal(t, ChassisID{Subtype: test.subtype, Value: test.value}, chassis)
+		assert.Equal(t, test.text, chassis.String())
+
+		devUrn, err := chassis.ToUrnDev()
+		assert.Equal(t, test.urn, devUrn.FullName, test.text)
+		assert.Equal(t, test.urn == "", err != nil, test.text)
+	}
+}
+
+func TestParsePortID(t *testing.T) {
+	for _, test := range []struct {
+		subtype   PortIDSubtype
+		value     []byte
+		text      string
+		component string
+	}{
+		{PortInterfaceAlias, []byte("to core #1"), "to core #1", "ifalias.to.core.1"},
+		{PortComponent, []byte("1/1"), "1/1", "port.1.1"},
+		{PortMACAddress, decodeHex("0024be804ff2"), "00:24:be:80:4f:f2", "mac.0024be804ff2"},
+		{PortNetworkAddress, decodeHex("01c0000201"), "192.0.2.1", "addr.192.0.2.1"},
+		{PortNetworkAddress, decodeHex("0220010db8000000000000000000000001"), "2001:db8::1", "addr.0220010db8000000000000000000000001"},
+		{PortInterfaceName, []byte("ge-0/0/1.0"), "ge-0/0/1.0", "ifname.ge-0.0.1.0"},
+		{PortAgentCircuitID, decodeHex("0104000a0001"), "0104000a0001", "circuit.0104000a0001"},
+		{PortLocallyAssigned, []byte("___"), "___", "local.5f5f5f"},
+	} {
+		port, err := ParsePortID(tlv(TLVPortID, uint8(test.subtype), test.value))
+		if err != nil {
+			t.Fatalf("Failed to parse %s", test.text)
+			return
+		}
+		assert.Equal(t, PortID{Subtype: test.subtype, Value: test.value}, port)
+		assert.Equal(t, test.text, port.String())
+		component, err := port.Component()
+		if err != nil {
+			t.Fatalf("Failed to get component %s", test.text)
+			return
+		}
+		assert.Equal(t, test.component, component)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, input := range [][]byte{
+		nil,
+		{0x02},
+		decodeHex("0207040024be804f"),
+		decodeHex("0200"),
+		tlv(TLVPortID, uint8(PortInterfaceName), []byte("eth0")),
+		tlv(TLVChassisID, 0, []byte("eth0")),
+		tlv(TLVChassisID, 8, []byte("eth0")),
+		tlv(TLVChassisID, uint8(ChassisMACAddress), decodeHex("0024be804f")),
+		tlv(TLVChassisID, uint8(ChassisNetworkAddress), decodeHex("01c00002")),
+		tlv(TLVChassisID, uint8(ChassisInterfaceName), nil),
+	} {
+		_, err := ParseChassisID(input)
+		assert.NotNil(t, err, fmt.Sprintf("%x", input))
+	}
+
+	for _, input := range [][]byte{
+		tlv(TLVChassisID, uint8(ChassisInterfaceName), []byte("eth0")),
+		tlv(TLVPortID, uint8(PortMACAddress), decodeHex("0024be804ff1aa")),
+		tlv(TLVPortID, uint8(PortNetworkAddress), decodeHex("0220010db8")),
+	} {
+		_, err := ParsePortID(input)
+		assert.NotNil(t, err, fmt.Sprintf("%x", input))
+	}
+
+	chassis := tlv(TLVChassisID, uint8(ChassisInterfaceName), []byte("eth0"))
+	_, _, err := ParseLLDPDU(chassis)
+	assert.NotNil(t, err)
+
+	chassisID, _ := ParseChassisID(chassis)
+	_, err = ToUrnDev(chassisID, PortID{Subtype: PortInterfaceName, Value: []byte("eth1")})
+	assert.NotNil(t, err)
+	assert.Equal(t, "reserved", PortIDSubtype(0).String())
+
+	// Hand-built identifiers are validated before use
+	for _, port := range []PortID{{Subtype: PortNetworkAddress}, {Subtype: PortInterfaceName}, {Subtype: PortMACAddress, Value: []byte{1}}, {Subtype: 0, Value: []byte("eth0")}} {
+		_, err := port.Component()
+		assert.NotNil(t, err, port.Subtype.String())
+		assert.NotPanics(t, func() { _ = port.String() })
+	}
+
+	for _, chassis := range []ChassisID{{Subtype: ChassisMACAddress}, {Subtype: ChassisMACAddress, Value: []byte{1, 2, 3}}} {
+		_, err := chassis.ToUrnDev()
+		assert.NotNil(t, err, chassis.String())
+	}
+	assert.Equal(t, "", ChassisID{Subtype: ChassisNetworkAddress}.String())
+}
+
+func TestComponentSubtypes(t *testing.T) {
+	// The same value with different subtypes gives different components
+	alias, _ := PortID{Subtype: PortInterfaceAlias, Value: []byte("eth0")}.Component()
+	name, _ := PortID{Subtype: PortInterfaceName, Value: []byte("eth0")}.Component()
+	local, _ := PortID{Subtype: PortLocallyAssigned, Value: []byte("eth0")}.Component()
+	assert.Equal(t, []string{"ifalias.eth0", "ifname.eth0", "local.eth0"}, []string{alias, name, local})
+}
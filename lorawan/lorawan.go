@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package lorawan provides tools for parsing LoRaWAN DevEUI, JoinEUI and DevAddr identifiers.
+package lorawan
+
+import (
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+const Eui64RegEx = "^(([0-9A-Fa-f]{2}:){7}|([0-9A-Fa-f]{2}-){7}|([0-9A-Fa-f]{2}){7})[0-9A-Fa-f]{2}$"
+const DevAddrRegEx = "^[0-9A-Fa-f]{8}$"
+
+// ByteOrder defines in which order bytes are presented in textual form.
+type ByteOrder int
+
+const (
+	// MSB presents most significant byte first. This is the order used in LoRaWAN specifications and most user interfaces.
+	MSB ByteOrder = iota
+	// LSB presents least significant byte first. This is the order used on air and by some network servers.
+	LSB
+)
+
+// EUI64 is a 64-bit extended unique identifier used as DevEUI and JoinEUI (AppEUI in LoRaWAN 1.0), stored most significant byte first.
+type EUI64 [8]byte
+
+// DevAddr is a 32-bit device address assigned by the network, stored most significant byte first.
+type DevAddr [4]byte
+
+func reverse(data []byte) {
+	for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+		data[i], data[j] = data[j], data[i]
+	}
+}
+
+// ParseEUI64 parses DevEUI or JoinEUI given as 16 hex digits, optionally separated by ":" or "-", in the given byte order. If incorrectly formed EUI is given as input an error is returned.
+func ParseEUI64(name string, order ByteOrder) (EUI64, error) {
+	if match, _ := regexp.MatchString(Eui64RegEx, name); !match {
+		return EUI64{}, errors.New("invalid input (EUI-64)")
+	}
+
+	name = strings.NewReplacer(":", "", "-", "").Replace(name)
+
+	var out EUI64
+	if _, err := hex.Decode(out[:], []byte(name)); err != nil {
+		return EUI64{}, errors.New("invalid input (EUI-64)")
+	}
+
+	if order == LSB {
+		reverse(out[:])
+	}
+
+	return out, nil
+}
+
+// Format returns EUI as 16 lower case hex digits in the given byte order.
+func (e EUI64) Format(order ByteOrder) string {
+	if order == LSB {
+		reverse(e[:])
+	}
+
+	return hex.EncodeToString(e[:])
+}
+
+// String returns EUI as 16 lower case hex digits, most significant byte first.
+func (e EUI64) String() string {
+	return e.Format(MSB)
+}
+
+// ToUrnDev maps EUI into urn:dev:mac identifier. DevEUI is an EUI-64 so no conversion is needed.
+func (e EUI64) ToUrnDev() (rfc9039.UrnDev, error) {
+	return rfc9039.Parse(rfc9039.UrnDevPrefix + "mac:" + e.String())
+}
+
+// FromUrnDev extracts EUI from urn:dev:mac identifier. An error is returned for other subtypes.
+func FromUrnDev(devUrn rfc9039.UrnDev) (EUI64, error) {
+	if devUrn.Subtype != "mac" {
+		return EUI64{}, errors.New("invalid input (not mac)")
+	}
+
+	return ParseEUI64(devUrn.Eui64Identifier, MSB)
+}
+
+// ParseDevAddr parses DevAddr given as 8 hex digits in the given byte order. If incorrectly formed DevAddr is given as input an error is returned.
+func ParseDevAddr(name string, order ByteOrder) (DevAddr, error) {
+	if match, _ := regexp.MatchString(DevAddrRegEx, name); !match {
+		return DevAddr{}, errors.New("invalid input (DevAddr)")
+	}
+
+	var out DevAddr
+	if _, err := hex.Decode(out[:], []byte(name)); err != nil {
+		return DevAddr{}, errors.New("invalid input (DevAddr)")
+	}
+
+	if order == LSB {
+		reverse(out[:])
+	}
+
+	return out, nil
+}
+
+// Format returns DevAddr as 8 lower case hex digits in the given byte order.
+func (a DevAddr) Format(order ByteOrder) string {
+	if order == LSB {
+		reverse(a[:])
+	}
+
+	return hex.EncodeToString(a[:])
+}
+
+// String returns DevAddr as 8 lower case hex digits, most significant byte first.
+func (a DevAddr) String() string {
+	return a.Format(MSB)
+}
+
+// nwkIDBits lists NwkID field lengths indexed by NetID type, as specified in LoRaWAN Backend Interfaces TS002-1.1.0 Table 3.
+var nwkIDBits = [8]uint{6, 6, 9, 11, 12, 13, 15, 17}
+
+func (a DevAddr) uint32() uint32 {
+	return uint32(a[0])<<24 | uint32(a[1])<<16 | uint32(a[2])<<8 | uint32(a[3])
+}
+
+// NetIDType returns NetID type 0-7 encoded in the AddrPrefix of DevAddr. Value -1 is returned when the prefix is not valid (all eight leading bits set).
+func (a DevAddr) NetIDType() int {
+	for i := 0; i < 8; i++ {
+		if a[0]&(0x80>>i) == 0 {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// DevAddrFields captures decoded fields of DevAddr.
+type DevAddrFields struct {
+	// NetIDType is the NetID type 0-7 encoded in the AddrPrefix.
+	NetIDType int
+	// NwkID holds the least significant bits of the NetID of the network that assigned the DevAddr.
+	NwkID uint32
+	// NwkAddr is the network specific part of the DevAddr.
+	NwkAddr uint32
+}
+
+// Decode splits DevAddr into NetID type, NwkID and NwkAddr. If DevAddr has invalid prefix an error is returned.
+func (a DevAddr) Decode() (DevAddrFields, error) {
+	netIDType := a.NetIDType()
+	if netIDType < 0 {
+		return DevAddrFields{}, errors.New("invalid input (DevAddr prefix)")
+	}
+
+	idBits := nwkIDBits[netIDType]
+	addrBits := 32 - uint(netIDType+1) - idBits
+
+	return DevAddrFields{
+		NetIDType: netIDType,
+		NwkID:     (a.uint32() >> addrBits) & (1<<idBits - 1),
+		NwkAddr:   a.uint32() & (1<<addrBits - 1),
+	}, nil
+}
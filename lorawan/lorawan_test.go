@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package lorawan
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+func ExampleParseEUI64() {
+	devEui, _ := ParseEUI64("70b3d57ed0001234", MSB)
+	devUrn, _ := devEui.ToUrnDev()
+	fmt.Println(devUrn.FullName)
+	fmt.Println(devEui.Format(LSB))
+	// Output: urn:dev:mac:70b3d57ed0001234
+	// 341200d07ed5b370
+}
+
+func ExampleDevAddr_Decode() {
+	devAddr, _ := ParseDevAddr("26011234", MSB)
+	fields, _ := devAddr.Decode()
+	fmt.Println(fields.NetIDType)
+	fmt.Printf("%#x\n", fields.NwkID)
+	fmt.Printf("%#x\n", fields.NwkAddr)
+	// Output: 0
+	// 0x13
+	// 0x11234
+}
+
+func TestParseEUI64(t *testing.T) {
+	expected := EUI64{0x70, 0xb3, 0xd5, 0x7e, 0xd0, 0x00, 0x12, 0x34}
+
+	for _, input := range []string{"70b3d57ed0001234", "70B3D57ED0001234", "70:b3:d5:7e:d0:00:12:34", "70-B3-D5-7E-D0-00-12-34"} {
+		value, err := ParseEUI64(input, MSB)
+		if err != nil {
+			t.Fatalf("Failed to parse %s", input)
+			return
+		}
+		assert.Equal(t, expected, value)
+	}
+}
+
+func TestParseEUI64LSB(t *testing.T) {
+	value, err := ParseEUI64("341200d07ed5b370", LSB)
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, EUI64{0x70, 0xb3, 0xd5, 0x7e, 0xd0, 0x00, 0x12, 0x34}, value)
+	assert.Equal(t, "70b3d57ed0001234", value.String())
+	assert.Equal(t, "341200d07ed5b370", value.Format(LSB))
+}
+
+func TestParseEUI64Invalid(t *testing.T) {
+	for _, input := range []string{"", "70b3d57ed000123", "70b3d57ed000123400", "70b3d57ed000123g", "70:b3-d5:7e:d0:00:12:34"} {
+		value, err := ParseEUI64(input, MSB)
+		if err == nil {
+			t.Fatalf("Failed to reject %s", input)
+			return
+		}
+		assert.Equal(t, EUI64{}, value)
+	}
+}
+
+func TestEUI64ToUrnDev(t *testing.T) {
+	devEui := EUI64{0x70, 0xb3, 0xd5, 0x7e, 0xd0, 0x00, 0x12, 0x34}
+
+	value, err := devEui.ToUrnDev()
+	if err != nil {
+		t.Fatalf("Failed to convert")
+		return
+	}
+	assert.Equal(t, "urn:dev:mac:70b3d57ed0001234", value.FullName)
+	assert.Equal(t, "mac", value.Subtype)
+	assert.Equal(t, "70b3d57ed0001234", value.Eui64Identifier)
+}
+
+func TestFromUrnDev(t *testing.T) {
+	devUrn, _ := rfc9039.Parse("urn:dev:mac:70b3d57ed0001234_radio")
+
+	value, err := FromUrnDev(devUrn)
+	if err != nil {
+		t.Fatalf("Failed to convert")
+		return
+	}
+	assert.Equal(t, EUI64{0x70, 0xb3, 0xd5, 0x7e, 0xd0, 0x00, 0x12, 0x34}, value)
+}
+
+func TestFromUrnDevInvalidSubtype(t *testing.T) {
+	devUrn, _ := rfc9039.Parse("urn:dev:ow:10e2073a01080063")
+
+	value, err := FromUrnDev(devUrn)
+	assert.Error(t, err)
+	assert.Equal(t, EUI64{}, value)
+}
+
+func TestParseDevAddr(t *testing.T) {
+	value, err := ParseDevAddr("26011234", MSB)
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, DevAddr{0x26, 0x01, 0x12, 0x34}, value)
+	assert.Equal(t, "26011234", value.String())
+
+	value, err = ParseDevAddr("34120126", LSB)
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, DevAddr{0x26, 0x01, 0x12, 0x34}, value)
+	assert.Equal(t, "34120126", value.Format(LSB))
+}
+
+func TestParseDevAddrInvalid(t *testing.T) {
+	for _, input := range []string{"", "2601123", "260112345", "2601123x"} {
+		_, err := ParseDevAddr(input, MSB)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestDevAddrDecode(t *testing.T) {
+	tests := []struct {
+		input    DevAddr
+		expected DevAddrFields
+	}{
+		{DevAddr{0x26, 0x01, 0x12, 0x34}, DevAddrFields{NetIDType: 0, NwkID: 0x13, NwkAddr: 0x0011234}},
+		{DevAddr{0xbf, 0xff, 0xff, 0xff}, DevAddrFields{NetIDType: 1, NwkID: 0x3f, NwkAddr: 0xffffff}},
+		{DevAddr{0xc0, 0x20, 0x00, 0x01}, DevAddrFields{NetIDType: 2, NwkID: 0x002, NwkAddr: 0x00001}},
+		{DevAddr{0xe0, 0x04, 0x00, 0x02}, DevAddrFields{NetIDType: 3, NwkID: 0x002, NwkAddr: 0x00002}},
+		{DevAddr{0xf0, 0x01, 0x00, 0x03}, DevAddrFields{NetIDType: 4, NwkID: 0x002, NwkAddr: 0x0003}},
+		{DevAddr{0xf8, 0x00, 0x40, 0x04}, DevAddrFields{NetIDType: 5, NwkID: 0x002, NwkAddr: 0x0004}},
+		{DevAddr{0xfc, 0x00, 0x08, 0x05}, DevAddrFields{NetIDType: 6, NwkID: 0x002, NwkAddr: 0x005}},
+		{DevAddr{0xfe, 0x00, 0x01, 0x06}, DevAddrFields{NetIDType: 7, NwkID: 0x002, NwkAddr: 0x06}},
+	}
+
+	for _, test := range tests {
+		value, err := test.input.Decode()
+		if err != nil {
+			t.Fatalf("Failed to decode %s", test.input)
+			return
+		}
+		assert.Equal(t, test.expected, value, test.input.String())
+	}
+}
+
+func TestDevAddrDecodeInvalidPrefix(t *testing.T) {
+	devAddr := DevAddr{0xff, 0x00, 0x00, 0x00}
+
+	assert.Equal(t, -1, devAddr.NetIDType())
+
+	value, err := devAddr.Decode()
+	assert.Error(t, err)
+	assert.Equal(t, DevAddrFields{}, value)
+}
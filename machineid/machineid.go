@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package machineid provides tools for deriving application specific device identifiers from systemd machine-id without exposing it.
+package machineid
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+const IDRegEx = "^[0-9a-f]{32}$"
+const UUIDRegEx = "^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$"
+
+// DefaultPath is the location of machine-id file, see machine-id(5).
+const DefaultPath = "/etc/machine-id"
+
+// Subtype is the urn:dev otherbody subtype of application specific machine identifiers.
+const Subtype = "machineid"
+
+// ID captures 128-bit identifier such as machine-id or application id.
+type ID [16]byte
+
+// ParseID parses identifier in 32 hex digit form used in machine-id file, e.g. "b08dfa6083e7567a1921a715000001fb", or in UUID form, case-insensitive as accepted by systemd. If incorrectly formed identifier or all zero identifier is given as input an error is returned.
+func ParseID(name string) (ID, error) {
+	name = strings.ToLower(name)
+
+	if match, _ := regexp.MatchString(UUIDRegEx, name); match {
+		name = strings.ReplaceAll(name, "-", "")
+	}
+
+	if match, _ := regexp.MatchString(IDRegEx, name); !match {
+		return ID{}, errors.New("invalid input (id)")
+	}
+
+	out := ID{}
+	hex.Decode(out[:], []byte(name))
+
+	if out == (ID{}) {
+		return ID{}, errors.New("invalid input (null id)")
+	}
+
+	return out, nil
+}
+
+// String returns identifier as 32 lower case hex digits.
+func (id ID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// UUID returns identifier in "3ee23c8d-1c5e-4fd0-b6a1-bd8a0c9e1d4c" form.
+func (id ID) UUID() string {
+	text := id.String()
+
+	return text[0:8] + "-" + text[8:12] + "-" + text[12:16] + "-" + text[16:20] + "-" + text[20:32]
+}
+
+// Read reads machine-id from path, normally DefaultPath. File content has to be single identifier optionally followed by a newline. Uninitialized machine-id, e.g. "uninitialized" written during first boot, gives an error.
+func Read(path string) (ID, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ID{}, err
+	}
+
+	return ParseID(strings.TrimSuffix(string(data), "\n"))
+}
+
+// AppSpecific derives application specific identifier from machineID and appID as sd_id128_get_machine_app_specific(3) does: HMAC-SHA256 keyed with machine-id over application id, truncated to 128 bits and formatted as version 4 UUID. The result is stable for the machine and application but machine-id can not be recovered from it, and identifiers of different applications can not be correlated.
+func AppSpecific(machineID ID, appID ID) ID {
+	mac := hmac.New(sha256.New, machineID[:])
+	mac.Write(appID[:])
+
+	out := ID{}
+	copy(out[:], mac.Sum(nil))
+
+	out[6] = (out[6] & 0x0f) | 0x40
+	out[8] = (out[8] & 0x3f) | 0x80
+
+	return out
+}
+
+// ReadAppSpecific reads machine-id from path and derives application specific identifier for appID.
+func ReadAppSpecific(path string, appID ID) (ID, error) {
+	machineID, err := Read(path)
+	if err != nil {
+		return ID{}, err
+	}
+
+	return AppSpecific(machineID, appID), nil
+}
+
+// ToUrnDev builds urn:dev otherbody identifier, for example "urn:dev:machineid:b6107d24bc2545358ffb27c29393666e". Only application specific identifiers should be exposed this way, never machine-id itself.
+func (id ID) ToUrnDev() (rfc9039.UrnDev, error) {
+	return rfc9039.Parse(rfc9039.UrnDevPrefix + Subtype + ":" + id.String())
+}
+
+// AppSpecificUrnDev reads machine-id from path and returns urn:dev of the application specific identifier for appID, see ReadAppSpecific and ToUrnDev.
+func AppSpecificUrnDev(path string, appID ID) (rfc9039.UrnDev, error) {
+	id, err := ReadAppSpecific(path, appID)
+	if err != nil {
+		return rfc9039.UrnDev{}, err
+	}
+
+	return id.ToUrnDev()
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package machineid
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeMachineID(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "machine-id")
+	if err := os.WriteFile(path, []byte(content), 0o444); err != nil {
+		t.Fatalf("Failed to write")
+	}
+
+	return path
+}
+
+func ExampleAppSpecific() {
+	machineID, _ := ParseID("b08dfa6083e7567a1921a715000001fb")
+	appID, _ := ParseID("3ee23c8d-1c5e-4fd0-b6a1-bd8a0c9e1d4c")
+
+	id := AppSpecific(machineID, appID)
+	fmt.Println(id.UUID())
+
+	devUrn, _ := id.ToUrnDev()
+	fmt.Println(devUrn.FullName)
+	// Output: b6107d24-bc25-4535-8ffb-27c29393666e
+	// urn:dev:machineid:b6107d24bc2545358ffb27c29393666e
+}
+
+func TestParseID(t *testing.T) {
+	expected := ID{0x3e, 0xe2, 0x3c, 0x8d, 0x1c, 0x5e, 0x4f, 0xd0, 0xb6, 0xa1, 0xbd, 0x8a, 0x0c, 0x9e, 0x1d, 0x4c}
+
+	for _, input := range []string{"3ee23c8d1c5e4fd0b6a1bd8a0c9e1d4c", "3EE23C8D-1C5E-4FD0-B6A1-BD8A0C9E1D4C"} {
+		id, err := ParseID(input)
+		if err != nil {
+			t.Fatalf("Failed to parse %s", input)
+			return
+		}
+		assert.Equal(t, expected, id)
+		assert.Equal(t, "3ee23c8d1c5e4fd0b6a1bd8a0c9e1d4c", id.String())
+	}
+}
+
+func TestParseIDInvalid(t *testing.T) {
+	for _, input := range []string{"", "uninitialized", "3ee23c8d1c5e4fd0b6a1bd8a0c9e1d4", "3ee23c8d1c5e4fd0b6a1bd8a0c9e1d4cc", "3ee23c8d-1c5e4fd0-b6a1-bd8a0c9e1d4c", "00000000000000000000000000000000"} {
+		_, err := ParseID(input)
+		assert.NotNil(t, err, input)
+	}
+}
+
+func TestAppSpecificUrnDev(t *testing.T) {
+	appID, _ := ParseID("3ee23c8d1c5e4fd0b6a1bd8a0c9e1d4c")
+	otherAppID, _ := ParseID("a3d8f3c2e1b44f2f9c1d0e7b6a5f4e3d")
+
+	devUrn, err := AppSpecificUrnDev(writeMachineID(t, "b08dfa6083e7567a1921a715000001fb\n"), appID)
+	if err != nil {
+		t.Fatalf("Failed to derive")
+		return
+	}
+	assert.Equal(t, "urn:dev:machineid:b6107d24bc2545358ffb27c29393666e", devUrn.FullName)
+	assert.Equal(t, "machineid", devUrn.Subtype)
+
+	other, _ := AppSpecificUrnDev(writeMachineID(t, "b08dfa6083e7567a1921a715000001fb"), otherAppID)
+	assert.NotEqual(t, devUrn.FullName, other.FullName)
+
+	for _, content := range []string{"", "uninitialized\n", "b08dfa6083e7567a1921a715000001fb\n\n"} {
+		_, err := AppSpecificUrnDev(writeMachineID(t, content), appID)
+		assert.NotNil(t, err, content)
+	}
+
+	_, err = ReadAppSpecific(filepath.Join(t.TempDir(), "missing"), appID)
+	assert.NotNil(t, err)
+}
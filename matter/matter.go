@@ -0,0 +1,407 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package matter provides tools for decoding Matter onboarding payloads and manual pairing codes.
+package matter
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+const QRCodePrefix = "MT:"
+
+const Base38Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ-."
+
+// CommissioningFlow defines how device is put into commissioning mode.
+type CommissioningFlow uint8
+
+const (
+	// StandardFlow devices are in commissioning mode when powered on.
+	StandardFlow CommissioningFlow = 0
+	// UserIntentFlow devices need user action to enter commissioning mode.
+	UserIntentFlow CommissioningFlow = 1
+	// CustomFlow devices need vendor specific instructions to enter commissioning mode.
+	CustomFlow CommissioningFlow = 2
+)
+
+// Discovery capability bits of the QR code payload.
+const (
+	DiscoverySoftAP    = 0x01
+	DiscoveryBLE       = 0x02
+	DiscoveryOnNetwork = 0x04
+)
+
+// Payload captures decoded fields of "MT:" QR code onboarding payload.
+type Payload struct {
+	// Version is the payload format version, currently always 0.
+	Version uint8
+	// VendorID is the Connectivity Standards Alliance assigned vendor identifier.
+	VendorID uint16
+	// ProductID is the vendor assigned product identifier.
+	ProductID uint16
+	// CommissioningFlow tells how device enters commissioning mode.
+	CommissioningFlow CommissioningFlow
+	// DiscoveryCapabilities is a bitmask of DiscoverySoftAP, DiscoveryBLE and DiscoveryOnNetwork.
+	DiscoveryCapabilities uint8
+	// Discriminator is the 12-bit discriminator used to find the device during commissioning.
+	Discriminator uint16
+	// Passcode is the setup passcode.
+	Passcode uint32
+	// SerialNumber captures value of optional serial number TLV element, empty if not present.
+	SerialNumber string
+	// TLVData captures raw optional TLV data following the packed fields.
+	TLVData []byte
+}
+
+// ManualCode captures decoded fields of 11 or 21 digit manual pairing code.
+type ManualCode struct {
+	// HasVendorProduct is true for 21 digit codes which carry VendorID and ProductID.
+	HasVendorProduct bool
+	// VendorID is the Connectivity Standards Alliance assigned vendor identifier, zero if not present.
+	VendorID uint16
+	// ProductID is the vendor assigned product identifier, zero if not present.
+	ProductID uint16
+	// ShortDiscriminator is the 4 most significant bits of the 12-bit discriminator.
+	ShortDiscriminator uint8
+	// Passcode is the setup passcode.
+	Passcode uint32
+}
+
+// VendorTable maps Connectivity Standards Alliance vendor IDs to IANA private enterprise numbers.
+type VendorTable map[uint16]uint32
+
+var invalidPasscodes = []uint32{
+	0, 11111111, 22222222, 33333333, 44444444, 55555555,
+	66666666, 77777777, 88888888, 99999999, 12345678, 87654321,
+}
+
+func isValidPasscode(passcode uint32) bool {
+	if passcode > 99999998 {
+		return false
+	}
+
+	for _, invalid := range invalidPasscodes {
+		if passcode == invalid {
+			return false
+		}
+	}
+
+	return true
+}
+
+// DecodeBase38 decodes Base38 encoded string as specified in Matter Core Specification 5.1.3.1. Each 5 character chunk encodes 3 bytes, trailing 4 and 2 character chunks encode 2 and 1 bytes.
+func DecodeBase38(name string) ([]byte, error) {
+	out := []byte{}
+
+	for len(name) > 0 {
+		var chunkLen, byteCount int
+		switch {
+		case len(name) >= 5:
+			chunkLen, byteCount = 5, 3
+		case len(name) == 4:
+			chunkLen, byteCount = 4, 2
+		case len(name) == 2:
+			chunkLen, byteCount = 2, 1
+		default:
+			return nil, errors.New("invalid input (base38 length)")
+		}
+
+		var value uint32
+		for i := chunkLen - 1; i >= 0; i-- {
+			digit := strings.IndexByte(Base38Alphabet, name[i])
+			if digit < 0 {
+				return nil, errors.New("invalid input (base38 character)")
+			}
+			value = value*38 + uint32(digit)
+		}
+
+		if value >= 1<<(8*byteCount) {
+			return nil, errors.New("invalid input (base38 value)")
+		}
+
+		for i := 0; i < byteCount; i++ {
+			out = append(out, byte(value>>(8*i)))
+		}
+
+		name = name[chunkLen:]
+	}
+
+	return out, nil
+}
+
+// bitReader reads little endian bit fields starting from the least significant bit of the first byte.
+type bitReader struct {
+	data   []byte
+	offset uint
+}
+
+func (r *bitReader) read(bits uint) uint32 {
+	var out uint32
+	for i := uint(0); i < bits; i++ {
+		bit := (r.data[(r.offset+i)/8] >> ((r.offset + i) % 8)) & 1
+		out |= uint32(bit) << i
+	}
+	r.offset += bits
+
+	return out
+}
+
+// ParseQRCode decodes "MT:" prefixed QR code onboarding payload. Concatenated payloads separated by "*" are not supported. If incorrectly formed payload is given as input an error is returned.
+func ParseQRCode(name string) (Payload, error) {
+	if !strings.HasPrefix(name, QRCodePrefix) {
+		return Payload{}, errors.New("invalid input (missing MT:)")
+	}
+
+	if strings.Contains(name, "*") {
+		return Payload{}, errors.New("invalid input (concatenated payload)")
+	}
+
+	data, err := DecodeBase38(name[len(QRCodePrefix):])
+	if err != nil {
+		return Payload{}, err
+	}
+
+	// 3 + 16 + 16 + 2 + 8 + 12 + 27 + 4 bits of packed fields
+	if len(data) < 11 {
+		return Payload{}, errors.New("invalid input (payload length)")
+	}
+
+	r := bitReader{data: data}
+
+	out := Payload{}
+	out.Version = uint8(r.read(3))
+	out.VendorID = uint16(r.read(16))
+	out.ProductID = uint16(r.read(16))
+	out.CommissioningFlow = CommissioningFlow(r.read(2))
+	out.DiscoveryCapabilities = uint8(r.read(8))
+	out.Discriminator = uint16(r.read(12))
+	out.Passcode = r.read(27)
+
+	if r.read(4) != 0 {
+		return Payload{}, errors.New("invalid input (padding)")
+	}
+
+	if out.Version != 0 {
+		return Payload{}, errors.New("invalid input (version)")
+	}
+
+	if out.CommissioningFlow > CustomFlow {
+		return Payload{}, errors.New("invalid input (commissioning flow)")
+	}
+
+	if !isValidPasscode(out.Passcode) {
+		return Payload{}, errors.New("invalid input (passcode)")
+	}
+
+	out.TLVData = data[11:]
+	if len(out.TLVData) > 0 {
+		out.SerialNumber, err = findSerialNumber(out.TLVData)
+		if err != nil {
+			return Payload{}, err
+		}
+	}
+
+	return out, nil
+}
+
+// findSerialNumber walks optional data TLV structure and returns value of serial number element (context tag 0x00) if present.
+func findSerialNumber(data []byte) (string, error) {
+	// Optional data is an anonymous structure
+	if data[0] != 0x15 {
+		return "", errors.New("invalid input (TLV)")
+	}
+
+	depth := 0
+	for pos := 1; pos < len(data); {
+		control := data[pos]
+		tagControl := control >> 5
+		elementType := control & 0x1f
+		pos++
+
+		tagLen := []int{0, 1, 2, 4, 2, 4, 6, 8}[tagControl]
+		if pos+tagLen > len(data) {
+			return "", errors.New("invalid input (TLV)")
+		}
+		isSerialTag := depth == 0 && tagControl == 1 && data[pos] == 0x00
+		pos += tagLen
+
+		var value []byte
+		switch {
+		case elementType <= 0x07:
+			// signed and unsigned integers
+			size := 1 << (elementType & 0x03)
+			if pos+size > len(data) {
+				return "", errors.New("invalid input (TLV)")
+			}
+			value = data[pos : pos+size]
+			pos += size
+
+			if isSerialTag && elementType >= 0x04 {
+				buf := make([]byte, 8)
+				copy(buf, value)
+				return strconv.FormatUint(binary.LittleEndian.Uint64(buf), 10), nil
+			}
+
+		case elementType == 0x08 || elementType == 0x09 || elementType == 0x14:
+			// booleans and null
+
+		case elementType == 0x0a || elementType == 0x0b:
+			// floating point
+			pos += 4 << (elementType - 0x0a)
+
+		case elementType >= 0x0c && elementType <= 0x13:
+			// UTF-8 and octet strings
+			lenSize := 1 << (elementType & 0x03)
+			if pos+lenSize > len(data) {
+				return "", errors.New("invalid input (TLV)")
+			}
+			buf := make([]byte, 8)
+			copy(buf, data[pos:pos+lenSize])
+			pos += lenSize
+
+			size := binary.LittleEndian.Uint64(buf)
+			if size > uint64(len(data)-pos) {
+				return "", errors.New("invalid input (TLV)")
+			}
+			value = data[pos : pos+int(size)]
+			pos += int(size)
+
+			if isSerialTag && elementType <= 0x0f {
+				return string(value), nil
+			}
+
+		case elementType >= 0x15 && elementType <= 0x17:
+			depth++
+
+		case elementType == 0x18:
+			if depth == 0 {
+				return "", nil
+			}
+			depth--
+
+		default:
+			return "", errors.New("invalid input (TLV)")
+		}
+	}
+
+	return "", errors.New("invalid input (TLV)")
+}
+
+var verhoeffD = [10][10]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	{1, 2, 3, 4, 0, 6, 7, 8, 9, 5},
+	{2, 3, 4, 0, 1, 7, 8, 9, 5, 6},
+	{3, 4, 0, 1, 2, 8, 9, 5, 6, 7},
+	{4, 0, 1, 2, 3, 9, 5, 6, 7, 8},
+	{5, 9, 8, 7, 6, 0, 4, 3, 2, 1},
+	{6, 5, 9, 8, 7, 1, 0, 4, 3, 2},
+	{7, 6, 5, 9, 8, 2, 1, 0, 4, 3},
+	{8, 7, 6, 5, 9, 3, 2, 1, 0, 4},
+	{9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+}
+
+var verhoeffP = [8][10]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	{1, 5, 7, 6, 2, 8, 3, 0, 9, 4},
+	{5, 8, 0, 3, 7, 9, 6, 1, 4, 2},
+	{8, 9, 1, 6, 0, 4, 3, 5, 2, 7},
+	{9, 4, 5, 3, 1, 2, 6, 8, 7, 0},
+	{4, 2, 8, 6, 5, 7, 3, 9, 0, 1},
+	{2, 7, 9, 3, 8, 0, 6, 4, 1, 5},
+	{7, 0, 4, 6, 9, 1, 3, 2, 5, 8},
+}
+
+var verhoeffInv = [10]int{0, 4, 3, 2, 1, 5, 6, 7, 8, 9}
+
+// VerhoeffCheckDigit calculates Verhoeff check digit for given decimal digits.
+func VerhoeffCheckDigit(digits string) byte {
+	c := 0
+	for i := 0; i < len(digits); i++ {
+		c = verhoeffD[c][verhoeffP[(i+1)%8][digits[len(digits)-1-i]-'0']]
+	}
+
+	return byte('0' + verhoeffInv[c])
+}
+
+// ParseManualCode decodes 11 or 21 digit manual pairing code. Dashes and spaces used for display grouping are ignored. If incorrectly formed code or code with invalid check digit is given as input an error is returned.
+func ParseManualCode(name string) (ManualCode, error) {
+	name = strings.NewReplacer("-", "", " ", "").Replace(name)
+
+	if len(name) != 11 && len(name) != 21 {
+		return ManualCode{}, errors.New("invalid input (length)")
+	}
+
+	for i := 0; i < len(name); i++ {
+		if name[i] < '0' || name[i] > '9' {
+			return ManualCode{}, errors.New("invalid input (digits)")
+		}
+	}
+
+	if VerhoeffCheckDigit(name[:len(name)-1]) != name[len(name)-1] {
+		return ManualCode{}, errors.New("invalid input (check digit)")
+	}
+
+	chunk := func(start, end int) uint32 {
+		value, _ := strconv.ParseUint(name[start:end], 10, 32)
+		return uint32(value)
+	}
+
+	chunk1 := chunk(0, 1)
+	chunk2 := chunk(1, 6)
+	chunk3 := chunk(6, 10)
+
+	// Version bit must be zero
+	if chunk1 > 7 || chunk2 > 0xffff || chunk3 > 0x1fff {
+		return ManualCode{}, errors.New("invalid input (chunk)")
+	}
+
+	out := ManualCode{}
+	out.HasVendorProduct = chunk1&0x04 != 0
+	out.ShortDiscriminator = uint8((chunk1&0x03)<<2 | chunk2>>14)
+	out.Passcode = chunk3<<14 | chunk2&0x3fff
+
+	if out.HasVendorProduct != (len(name) == 21) {
+		return ManualCode{}, errors.New("invalid input (length)")
+	}
+
+	if out.HasVendorProduct {
+		vendorID := chunk(10, 15)
+		productID := chunk(15, 20)
+		if vendorID > 0xffff || productID > 0xffff {
+			return ManualCode{}, errors.New("invalid input (vendor or product)")
+		}
+		out.VendorID = uint16(vendorID)
+		out.ProductID = uint16(productID)
+	}
+
+	if !isValidPasscode(out.Passcode) {
+		return ManualCode{}, errors.New("invalid input (passcode)")
+	}
+
+	return out, nil
+}
+
+// ShortDiscriminator returns the 4 most significant bits of the discriminator as used in manual pairing codes.
+func (p Payload) ShortDiscriminator() uint8 {
+	return uint8(p.Discriminator >> 8)
+}
+
+// ToUrnDev maps vendor ID, product ID and serial number into urn:dev:ops identifier using the vendor ID to private enterprise number table. Product is formatted as four upper case hex digits like product IDs are shown in Matter tooling. An error is returned when vendor is not in the table or serial number is not valid identifier.
+func (t VendorTable) ToUrnDev(vendorID uint16, productID uint16, serial string) (rfc9039.UrnDev, error) {
+	pen, ok := t[vendorID]
+	if !ok {
+		return rfc9039.UrnDev{}, fmt.Errorf("invalid input (unknown vendor %#04x)", vendorID)
+	}
+
+	if match, _ := regexp.MatchString(rfc9039.DevUrnReservedRegEx, serial); !match {
+		return rfc9039.UrnDev{}, errors.New("invalid input (serial)")
+	}
+
+	return rfc9039.Parse(fmt.Sprintf("%sops:%d-%04X-%s", rfc9039.UrnDevPrefix, pen, productID, serial))
+}
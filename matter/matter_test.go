@@ -0,0 +1,230 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package matter
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func ExampleParseQRCode() {
+	payload, _ := ParseQRCode("MT:Y.K9042C00KA0648G00")
+	fmt.Printf("%#04x %#04x\n", payload.VendorID, payload.ProductID)
+	fmt.Println(payload.Discriminator)
+	fmt.Println(payload.Passcode)
+	// Output: 0xfff1 0x8000
+	// 3840
+	// 20202021
+}
+
+func ExampleParseManualCode() {
+	code, _ := ParseManualCode("3497-011-2332")
+	fmt.Println(code.ShortDiscriminator)
+	fmt.Println(code.Passcode)
+	// Output: 15
+	// 20202021
+}
+
+func ExampleVendorTable_ToUrnDev() {
+	table := VendorTable{0xfff1: 32473}
+	devUrn, _ := table.ToUrnDev(0xfff1, 0x8000, "SN1234")
+	fmt.Println(devUrn.FullName)
+	// Output: urn:dev:ops:32473-8000-SN1234
+}
+
+// encodeBase38 is the inverse of DecodeBase38 and used for building test payloads.
+func encodeBase38(data []byte) string {
+	out := ""
+	for len(data) > 0 {
+		byteCount := 3
+		if len(data) < 3 {
+			byteCount = len(data)
+		}
+		charCount := map[int]int{3: 5, 2: 4, 1: 2}[byteCount]
+
+		var value uint32
+		for i := 0; i < byteCount; i++ {
+			value |= uint32(data[i]) << (8 * i)
+		}
+		for i := 0; i < charCount; i++ {
+			out += string(Base38Alphabet[value%38])
+			value /= 38
+		}
+
+		data = data[byteCount:]
+	}
+
+	return out
+}
+
+func TestDecodeBase38(t *testing.T) {
+	value, err := DecodeBase38("")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{}, value)
+
+	input := []byte{0x00, 0x01, 0x02, 0xfe, 0xff, 0x10, 0xab}
+	value, err = DecodeBase38(encodeBase38(input))
+	assert.NoError(t, err)
+	assert.Equal(t, input, value)
+
+	value, err = DecodeBase38("00")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x00}, value)
+}
+
+func TestDecodeBase38Invalid(t *testing.T) {
+	for _, input := range []string{"A", "ABC", "ABCDEF", "abcde", "A*B", "....."} {
+		_, err := DecodeBase38(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestParseQRCode(t *testing.T) {
+	value, err := ParseQRCode("MT:-24J0AFN00KA0648G00")
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, uint8(0), value.Version)
+	assert.Equal(t, uint16(0xfff1), value.VendorID)
+	assert.Equal(t, uint16(0x8001), value.ProductID)
+	assert.Equal(t, StandardFlow, value.CommissioningFlow)
+	assert.Equal(t, uint8(DiscoveryOnNetwork), value.DiscoveryCapabilities)
+	assert.Equal(t, uint16(3840), value.Discriminator)
+	assert.Equal(t, uint8(15), value.ShortDiscriminator())
+	assert.Equal(t, uint32(20202021), value.Passcode)
+	assert.Equal(t, "", value.SerialNumber)
+	assert.Equal(t, []byte{}, value.TLVData)
+}
+
+func TestParseQRCodeWithSerialNumber(t *testing.T) {
+	packed, _ := DecodeBase38("Y.K9042C00KA0648G00")
+	tlv := []byte{0x15, 0x2c, 0x00, 0x06, 'S', 'N', '1', '2', '3', '4', 0x18}
+
+	value, err := ParseQRCode(QRCodePrefix + encodeBase38(append(packed, tlv...)))
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, uint16(0xfff1), value.VendorID)
+	assert.Equal(t, uint16(0x8000), value.ProductID)
+	assert.Equal(t, "SN1234", value.SerialNumber)
+	assert.Equal(t, tlv, value.TLVData)
+}
+
+func TestParseQRCodeWithNumericSerialNumber(t *testing.T) {
+	packed, _ := DecodeBase38("Y.K9042C00KA0648G00")
+	// vendor specific element before serial number
+	tlv := []byte{0x15, 0x2c, 0x81, 0x01, 'x', 0x26, 0x00, 0x40, 0xe2, 0x01, 0x00, 0x18}
+
+	value, err := ParseQRCode(QRCodePrefix + encodeBase38(append(packed, tlv...)))
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, "123456", value.SerialNumber)
+}
+
+func TestParseQRCodeInvalid(t *testing.T) {
+	packed, _ := DecodeBase38("Y.K9042C00KA0648G00")
+
+	for _, input := range []string{
+		"",
+		"Y.K9042C00KA0648G00",
+		"MT:Y.K9042C00KA0648G",
+		"MT:Y.K9042C00KA0648G00*Y.K9042C00KA0648G00",
+		QRCodePrefix + encodeBase38(append(packed, 0x15, 0x2c, 0x00, 0x10)),
+		QRCodePrefix + encodeBase38(append(packed, 0x17)),
+	} {
+		value, err := ParseQRCode(input)
+		assert.Error(t, err, input)
+		assert.Equal(t, Payload{}, value)
+	}
+}
+
+func TestParseQRCodeInvalidPasscode(t *testing.T) {
+	packed, _ := DecodeBase38("Y.K9042C00KA0648G00")
+	// Passcode occupies bits 57-83, set all of them
+	packed[7] |= 0xfe
+	packed[8] = 0xff
+	packed[9] = 0xff
+	packed[10] |= 0x0f
+
+	_, err := ParseQRCode(QRCodePrefix + encodeBase38(packed))
+	assert.Error(t, err)
+}
+
+func TestVerhoeffCheckDigit(t *testing.T) {
+	assert.Equal(t, byte('3'), VerhoeffCheckDigit("236"))
+	assert.Equal(t, byte('2'), VerhoeffCheckDigit("3497011233"))
+}
+
+func TestParseManualCode(t *testing.T) {
+	value, err := ParseManualCode("34970112332")
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, false, value.HasVendorProduct)
+	assert.Equal(t, uint16(0), value.VendorID)
+	assert.Equal(t, uint16(0), value.ProductID)
+	assert.Equal(t, uint8(15), value.ShortDiscriminator)
+	assert.Equal(t, uint32(20202021), value.Passcode)
+}
+
+func TestParseManualCodeWithVendorProduct(t *testing.T) {
+	digits := "7497011233" + "65521" + "32768"
+	value, err := ParseManualCode(digits + string(VerhoeffCheckDigit(digits)))
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, true, value.HasVendorProduct)
+	assert.Equal(t, uint16(0xfff1), value.VendorID)
+	assert.Equal(t, uint16(0x8000), value.ProductID)
+	assert.Equal(t, uint8(15), value.ShortDiscriminator)
+	assert.Equal(t, uint32(20202021), value.Passcode)
+}
+
+func TestParseManualCodeInvalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"34970112333",
+		"3497011233",
+		"3497O112332",
+		// VID/PID flag set in 11 digit code
+		"7497011233" + string(VerhoeffCheckDigit("7497011233")),
+		// VID/PID flag not set in 21 digit code
+		"34970112336552132768" + string(VerhoeffCheckDigit("34970112336552132768")),
+		// Passcode 00000000
+		"0000000000" + string(VerhoeffCheckDigit("0000000000")),
+	} {
+		value, err := ParseManualCode(input)
+		assert.Error(t, err, input)
+		assert.Equal(t, ManualCode{}, value)
+	}
+}
+
+func TestVendorTableToUrnDev(t *testing.T) {
+	table := VendorTable{0xfff1: 32473}
+
+	value, err := table.ToUrnDev(0xfff1, 0x000a, "SN1234")
+	if err != nil {
+		t.Fatalf("Failed to convert")
+		return
+	}
+	assert.Equal(t, "urn:dev:ops:32473-000A-SN1234", value.FullName)
+	assert.Equal(t, "32473", value.Organization)
+	assert.Equal(t, "000A", value.Product)
+	assert.Equal(t, "SN1234", value.Serial)
+
+	_, err = table.ToUrnDev(0xfff2, 0x8000, "SN1234")
+	assert.Error(t, err)
+
+	_, err = table.ToUrnDev(0xfff1, 0x8000, "")
+	assert.Error(t, err)
+
+	_, err = table.ToUrnDev(0xfff1, 0x8000, "SN_1234")
+	assert.Error(t, err)
+}
@@ -0,0 +1,267 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package mbus provides tools for parsing EN 13757 wired and wireless M-Bus secondary addresses and mapping meters into urn:dev identifiers.
+package mbus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+// ManufacturerRegEx matches 3 letter FLAG manufacturer code.
+const ManufacturerRegEx = "^[A-Z]{3}$"
+
+// AddressLength is the length of secondary address in bytes.
+const AddressLength = 8
+
+// MaxIdentification is the largest 8 digit identification number.
+const MaxIdentification = 99999999
+
+// Subtype is the urn:dev otherbody subtype of meters whose manufacturer has no Private Enterprise Number configured.
+const Subtype = "mbus"
+
+// Medium defines the device type of a meter.
+type Medium uint8
+
+// Media defined in EN 13757-3.
+const (
+	MediumOther                   Medium = 0x00
+	MediumOil                     Medium = 0x01
+	MediumElectricity             Medium = 0x02
+	MediumGas                     Medium = 0x03
+	MediumHeatOutlet              Medium = 0x04
+	MediumSteam                   Medium = 0x05
+	MediumWarmWater               Medium = 0x06
+	MediumWater                   Medium = 0x07
+	MediumHeatCostAllocator       Medium = 0x08
+	MediumCompressedAir           Medium = 0x09
+	MediumCoolingOutlet           Medium = 0x0a
+	MediumCoolingInlet            Medium = 0x0b
+	MediumHeatInlet               Medium = 0x0c
+	MediumHeatCooling             Medium = 0x0d
+	MediumBus                     Medium = 0x0e
+	MediumUnknown                 Medium = 0x0f
+	MediumCalorificValue          Medium = 0x14
+	MediumHotWater                Medium = 0x15
+	MediumColdWater               Medium = 0x16
+	MediumDualWater               Medium = 0x17
+	MediumPressure                Medium = 0x18
+	MediumADConverter             Medium = 0x19
+	MediumSmokeDetector           Medium = 0x1a
+	MediumRoomSensor              Medium = 0x1b
+	MediumGasDetector             Medium = 0x1c
+	MediumBreaker                 Medium = 0x20
+	MediumValve                   Medium = 0x21
+	MediumCustomerUnit            Medium = 0x25
+	MediumWasteWater              Medium = 0x28
+	MediumGarbage                 Medium = 0x29
+	MediumCommunicationController Medium = 0x31
+	MediumUnidirectionalRepeater  Medium = 0x32
+	MediumBidirectionalRepeater   Medium = 0x33
+	MediumRadioConverterSystem    Medium = 0x36
+	MediumRadioConverterMeter     Medium = 0x37
+)
+
+var mediumNames = map[Medium]string{
+	MediumOther:                   "other",
+	MediumOil:                     "oil",
+	MediumElectricity:             "electricity",
+	MediumGas:                     "gas",
+	MediumHeatOutlet:              "heat (outlet)",
+	MediumSteam:                   "steam",
+	MediumWarmWater:               "warm water",
+	MediumWater:                   "water",
+	MediumHeatCostAllocator:       "heat cost allocator",
+	MediumCompressedAir:           "compressed air",
+	MediumCoolingOutlet:           "cooling load meter (outlet)",
+	MediumCoolingInlet:            "cooling load meter (inlet)",
+	MediumHeatInlet:               "heat (inlet)",
+	MediumHeatCooling:             "heat / cooling load meter",
+	MediumBus:                     "bus / system component",
+	MediumUnknown:                 "unknown medium",
+	MediumCalorificValue:          "calorific value",
+	MediumHotWater:                "hot water",
+	MediumColdWater:               "cold water",
+	MediumDualWater:               "dual register (hot/cold) water meter",
+	MediumPressure:                "pressure",
+	MediumADConverter:             "A/D converter",
+	MediumSmokeDetector:           "smoke detector",
+	MediumRoomSensor:              "room sensor",
+	MediumGasDetector:             "gas detector",
+	MediumBreaker:                 "breaker (electricity)",
+	MediumValve:                   "valve (gas or water)",
+	MediumCustomerUnit:            "customer unit (display device)",
+	MediumWasteWater:              "waste water",
+	MediumGarbage:                 "garbage",
+	MediumCommunicationController: "communication controller",
+	MediumUnidirectionalRepeater:  "unidirectional repeater",
+	MediumBidirectionalRepeater:   "bidirectional repeater",
+	MediumRadioConverterSystem:    "radio converter (system side)",
+	MediumRadioConverterMeter:     "radio converter (meter side)",
+}
+
+// String returns name of the medium, or "reserved" for media without a name.
+func (m Medium) String() string {
+	if name, ok := mediumNames[m]; ok {
+		return name
+	}
+
+	return "reserved"
+}
+
+// Address captures M-Bus secondary address.
+type Address struct {
+	// Identification is the 8 digit identification number, usually the serial number of the meter.
+	Identification uint32
+	// Manufacturer is the 3 letter FLAG manufacturer code, e.g. "KAM".
+	Manufacturer string
+	// Version is the version, or generation, of the meter.
+	Version uint8
+	// Medium is the device type of the meter.
+	Medium Medium
+}
+
+// DecodeManufacturer decodes 3 letter manufacturer code packed into 16 bits, 5 bits for each letter with "A" as 1. If letters are not between "A" and "Z" an error is returned.
+func DecodeManufacturer(value uint16) (string, error) {
+	if value&0x8000 != 0 {
+		return "", errors.New("invalid input (manufacturer)")
+	}
+
+	out := ""
+	for shift := 10; shift >= 0; shift -= 5 {
+		letter := (value >> shift) & 0x1f
+		if letter < 1 || letter > 26 {
+			return "", errors.New("invalid input (manufacturer)")
+		}
+		out += string(rune('A' - 1 + letter))
+	}
+
+	return out, nil
+}
+
+// EncodeManufacturer packs 3 letter manufacturer code into 16 bits, see DecodeManufacturer. Case is ignored.
+func EncodeManufacturer(code string) (uint16, error) {
+	code = strings.ToUpper(code)
+	if match, _ := regexp.MatchString(ManufacturerRegEx, code); !match {
+		return 0, errors.New("invalid input (manufacturer)")
+	}
+
+	return uint16(code[0]-'A'+1)<<10 | uint16(code[1]-'A'+1)<<5 | uint16(code[2]-'A'+1), nil
+}
+
+// decodeBCD decodes identification number given as 4 byte BCD least significant byte first.
+func decodeBCD(data []byte) (uint32, error) {
+	out := uint32(0)
+
+	for index := len(data) - 1; index >= 0; index-- {
+		high, low := data[index]>>4, data[index]&0x0f
+		if high > 9 || low > 9 {
+			return 0, errors.New("invalid input (identification)")
+		}
+		out = out*100 + uint32(high)*10 + uint32(low)
+	}
+
+	return out, nil
+}
+
+func encodeBCD(value uint32) []byte {
+	out := make([]byte, 4)
+
+	for index := range out {
+		out[index] = byte(value%10) | byte(value/10%10)<<4
+		value /= 100
+	}
+
+	return out
+}
+
+func decode(identification []byte, manufacturer []byte, version byte, medium byte) (Address, error) {
+	id, err := decodeBCD(identification)
+	if err != nil {
+		return Address{}, err
+	}
+
+	code, err := DecodeManufacturer(binary.LittleEndian.Uint16(manufacturer))
+	if err != nil {
+		return Address{}, err
+	}
+
+	return Address{Identification: id, Manufacturer: code, Version: version, Medium: Medium(medium)}, nil
+}
+
+// ParseAddress parses wired M-Bus secondary address in wire order: identification number (4 bytes BCD), manufacturer (2 bytes), version and medium, multi-byte fields least significant byte first. If incorrectly formed address is given as input an error is returned.
+func ParseAddress(data []byte) (Address, error) {
+	if len(data) != AddressLength {
+		return Address{}, errors.New("invalid input (address length)")
+	}
+
+	return decode(data[0:4], data[4:6], data[6], data[7])
+}
+
+// ParseWirelessAddress parses wireless M-Bus link layer address in wire order: manufacturer (2 bytes), identification number (4 bytes BCD), version and device type, see ParseAddress.
+func ParseWirelessAddress(data []byte) (Address, error) {
+	if len(data) != AddressLength {
+		return Address{}, errors.New("invalid input (address length)")
+	}
+
+	return decode(data[2:6], data[0:2], data[6], data[7])
+}
+
+func (a Address) encode() ([]byte, []byte, error) {
+	if a.Identification > MaxIdentification {
+		return nil, nil, errors.New("invalid input (identification)")
+	}
+
+	manufacturer, err := EncodeManufacturer(a.Manufacturer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return encodeBCD(a.Identification), binary.LittleEndian.AppendUint16(nil, manufacturer), nil
+}
+
+// Bytes encodes address in wired M-Bus wire order, see ParseAddress.
+func (a Address) Bytes() ([]byte, error) {
+	identification, manufacturer, err := a.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append(identification, manufacturer...), a.Version, byte(a.Medium)), nil
+}
+
+// WirelessBytes encodes address in wireless M-Bus wire order, see ParseWirelessAddress.
+func (a Address) WirelessBytes() ([]byte, error) {
+	identification, manufacturer, err := a.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append(manufacturer, identification...), a.Version, byte(a.Medium)), nil
+}
+
+// String returns address as manufacturer, identification number, version and medium in hex, e.g. "KAM 12345678 1b 16".
+func (a Address) String() string {
+	return fmt.Sprintf("%s %08d %02x %02x", a.Manufacturer, a.Identification, a.Version, uint8(a.Medium))
+}
+
+// ToUrnDev maps meter into urn:dev:org identifier when the manufacturer has Private Enterprise Number in manufacturers, keyed by upper case FLAG code, for example "urn:dev:org:32473-12345678:1b:16", and otherwise into otherbody identifier with manufacturer, for example "urn:dev:mbus:KAM:12345678:1b:16". Identification number has 8 digits and version and medium are 2 hex digits, as all of them are needed for the address to be unique.
+func (a Address) ToUrnDev(manufacturers map[string]int) (rfc9039.UrnDev, error) {
+	if _, err := a.Bytes(); err != nil {
+		return rfc9039.UrnDev{}, err
+	}
+
+	code := strings.ToUpper(a.Manufacturer)
+	body := fmt.Sprintf("%08d:%02x:%02x", a.Identification, a.Version, uint8(a.Medium))
+
+	if pen, ok := manufacturers[code]; ok && pen > 0 {
+		return rfc9039.Parse(fmt.Sprintf("%sorg:%d-%s", rfc9039.UrnDevPrefix, pen, body))
+	}
+
+	return rfc9039.Parse(rfc9039.UrnDevPrefix + Subtype + ":" + code + ":" + body)
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package mbus
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func decodeHex(text string) []byte {
+	data, _ := hex.DecodeString(text)
+
+	return data
+}
+
+func ExampleParseAddress() {
+	address, _ := ParseAddress(decodeHex("785634122d2c1b16"))
+	fmt.Println(address, address.Medium)
+
+	devUrn, _ := address.ToUrnDev(nil)
+	fmt.Println(devUrn.FullName)
+
+	devUrn, _ = address.ToUrnDev(map[string]int{"KAM": 32473})
+	fmt.Println(devUrn.FullName)
+	// Output: KAM 12345678 1b 16 cold water
+	// urn:dev:mbus:KAM:12345678:1b:16
+	// urn:dev:org:32473-12345678:1b:16
+}
+
+func TestParseAddress(t *testing.T) {
+	for _, test := range []struct {
+		wired    string
+		wireless string
+		address  Address
+		medium   string
+	}{
+		{"785634122d2c1b16", "2d2c785634121b16", Address{Identification: 12345678, Manufacturer: "KAM", Version: 0x1b, Medium: MediumColdWater}, "cold water"},
+		{"01000000a8150004", "a815010000000004", Address{Identification: 1, Manufacturer: "EMH", Version: 0, Medium: MediumHeatOutlet}, "heat (outlet)"},
+		{"99999999210432ff", "21049999999932ff", Address{Identification: 99999999, Manufacturer: "AAA", Version: 0x32, Medium: 0xff}, "reserved"},
+	} {
+		address, err := ParseAddress(decodeHex(test.wired))
+		if err != nil {
+			t.Fatalf("Failed to parse %s", test.wired)
+			return
+		}
+		assert.Equal(t, test.address, address, test.wired)
+		assert.Equal(t, test.medium, address.Medium.String(), test.wired)
+
+		data, err := address.Bytes()
+		if err != nil {
+			t.Fatalf("Failed to encode %s", test.wired)
+			return
+		}
+		assert.Equal(t, test.wired, hex.EncodeToString(data))
+
+		wireless, _ := ParseWirelessAddress(decodeHex(test.wireless))
+		assert.Equal(t, test.address, wireless, test.wireless)
+
+		data, _ = address.WirelessBytes()
+		assert.Equal(t, test.wireless, hex.EncodeToString(data))
+	}
+}
+
+func TestParseAddressInvalid(t *testing.T) {
+	for _, input := range []string{"", "785634122d2c1b", "785634122d2c1b1600", "7856341a2d2c1b16", "f85634122d2c1b16", "7856341200001b16", "785634122dac1b16", "785634123f001b16"} {
+		_, err := ParseAddress(decodeHex(input))
+		assert.NotNil(t, err, input)
+	}
+}
+
+func TestManufacturer(t *testing.T) {
+	for code, value := range map[string]uint16{"KAM": 0x2c2d, "EMH": 0x15a8, "AAA": 0x0421, "ZZZ": 0x6b5a} {
+		encoded, err := EncodeManufacturer(code)
+		if err != nil {
+			t.Fatalf("Failed to encode %s", code)
+			return
+		}
+		assert.Equal(t, value, encoded, code)
+
+		decoded, err := DecodeManufacturer(value)
+		if err != nil {
+			t.Fatalf("Failed to decode %s", code)
+			return
+		}
+		assert.Equal(t, code, decoded)
+	}
+
+	for _, input := range []string{"", "KA", "KAMS", "K4M", "K-M"} {
+		_, err := EncodeManufacturer(input)
+		assert.NotNil(t, err, input)
+	}
+}
+
+func TestToUrnDev(t *testing.T) {
+	address := Address{Identification: 42, Manufacturer: "lug", Version: 4, Medium: MediumHeatOutlet}
+	manufacturers := map[string]int{"KAM": 32473}
+
+	devUrn, err := address.ToUrnDev(manufacturers)
+	if err != nil {
+		t.Fatalf("Failed to map")
+		return
+	}
+	assert.Equal(t, "urn:dev:mbus:LUG:00000042:04:04", devUrn.FullName)
+
+	manufacturers["LUG"] = 32473
+	devUrn, _ = address.ToUrnDev(manufacturers)
+	assert.Equal(t, "urn:dev:org:32473-00000042:04:04", devUrn.FullName)
+	assert.Equal(t, "32473", devUrn.Organization)
+
+	for _, input := range []Address{
+		{Identification: 100000000, Manufacturer: "KAM"},
+		{Identification: 1, Manufacturer: "KAMS"},
+		{Identification: 1},
+	} {
+		_, err := input.ToUrnDev(manufacturers)
+		assert.NotNil(t, err, input.String())
+	}
+}
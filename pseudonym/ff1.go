@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package pseudonym
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// ff1Rounds is the number of Feistel rounds of FF1.
+const ff1Rounds = 10
+
+// ff1MinDomain is the minimum size of the domain, radix to the power of numeral string length, accepted by FF1.
+const ff1MinDomain = 1000000
+
+// ff1Digits are the digits of numeral strings.
+const ff1Digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// ff1 implements FF1 format preserving encryption of NIST SP 800-38G with AES for numeral strings of radix 2 to 36 written with digits "0" to "9" and "a" to "z".
+type ff1 struct {
+	block cipher.Block
+	radix int
+}
+
+// newFF1 creates FF1 using AES key of 16, 24 or 32 bytes.
+func newFF1(key []byte, radix int) (*ff1, error) {
+	if radix < 2 || radix > 36 {
+		return nil, errors.New("invalid input (radix)")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.New("invalid input (key)")
+	}
+
+	return &ff1{block: block, radix: radix}, nil
+}
+
+// prf is the CBC-MAC of data with zero IV. Length of data is a multiple of the block size.
+func (f *ff1) prf(data []byte) []byte {
+	out := make([]byte, aes.BlockSize)
+
+	for start := 0; start < len(data); start += aes.BlockSize {
+		for i := range out {
+			out[i] ^= data[start+i]
+		}
+		f.block.Encrypt(out, out)
+	}
+
+	return out
+}
+
+// num returns value of numeral string in radix.
+func (f *ff1) num(digits string) *big.Int {
+	value, _ := new(big.Int).SetString(digits, f.radix)
+
+	return value
+}
+
+// str returns value as numeral string of length digits in radix.
+func (f *ff1) str(value *big.Int, length int) string {
+	digits := value.Text(f.radix)
+
+	return strings.Repeat("0", length-len(digits)) + digits
+}
+
+// round computes round function output y of round i from numeral string half, see steps 6.i to 6.iv of NIST SP 800-38G algorithm 7.
+func (f *ff1) round(header []byte, tweak []byte, i int, half string, b int, d int) *big.Int {
+	// Q = T || [0]^((-t-b-1) mod 16) || [i]^1 || [NUM(half)]^b
+	padding := (16 - (len(tweak)+b+1)%16) % 16
+	data := append([]byte{}, header...)
+	data = append(data, tweak...)
+	data = append(data, make([]byte, padding)...)
+	data = append(data, byte(i))
+	data = append(data, f.num(half).FillBytes(make([]byte, b))...)
+
+	r := f.prf(data)
+
+	// S = first d bytes of R || CIPH(R xor [1]^16) || CIPH(R xor [2]^16) ...
+	s := append([]byte{}, r...)
+	for j := 1; len(s) < d; j++ {
+		block := make([]byte, aes.BlockSize)
+		binary.BigEndian.PutUint64(block[8:], uint64(j))
+		for k := range block {
+			block[k] ^= r[k]
+		}
+		f.block.Encrypt(block, block)
+		s = append(s, block...)
+	}
+
+	return new(big.Int).SetBytes(s[:d])
+}
+
+// transform encrypts or decrypts numeral string with tweak, see NIST SP 800-38G algorithms 7 and 8.
+func (f *ff1) transform(digits string, tweak []byte, decrypt bool) (string, error) {
+	n := len(digits)
+	if n < 2 || n > 1<<16 {
+		return "", errors.New("invalid input (length)")
+	}
+
+	radix := big.NewInt(int64(f.radix))
+	domain := new(big.Int).Exp(radix, big.NewInt(int64(n)), nil)
+	if domain.Cmp(big.NewInt(ff1MinDomain)) < 0 {
+		return "", errors.New("invalid input (domain)")
+	}
+
+	for _, digit := range digits {
+		if !strings.ContainsRune(ff1Digits[:f.radix], digit) {
+			return "", errors.New("invalid input (digit)")
+		}
+	}
+
+	u := n / 2
+	v := n - u
+	a, b := digits[:u], digits[u:]
+
+	// b = ceil(ceil(v * log2(radix)) / 8) and d = 4 * ceil(b / 4) + 4
+	maxHalf := new(big.Int).Exp(radix, big.NewInt(int64(v)), nil)
+	bytesB := (new(big.Int).Sub(maxHalf, big.NewInt(1)).BitLen() + 7) / 8
+	bytesD := 4*((bytesB+3)/4) + 4
+
+	// P = [1]^1 || [2]^1 || [1]^1 || [radix]^3 || [10]^1 || [u mod 256]^1 || [n]^4 || [t]^4
+	header := []byte{1, 2, 1, 0, byte(f.radix >> 8), byte(f.radix), ff1Rounds, byte(u)}
+	header = binary.BigEndian.AppendUint32(header, uint32(n))
+	header = binary.BigEndian.AppendUint32(header, uint32(len(tweak)))
+
+	for step := 0; step < ff1Rounds; step++ {
+		i := step
+		if decrypt {
+			i = ff1Rounds - 1 - step
+		}
+
+		m := u
+		if i%2 == 1 {
+			m = v
+		}
+		modulus := new(big.Int).Exp(radix, big.NewInt(int64(m)), nil)
+
+		if decrypt {
+			y := f.round(header, tweak, i, a, bytesB, bytesD)
+			c := new(big.Int).Sub(f.num(b), y)
+			a, b = f.str(c.Mod(c, modulus), m), a
+		} else {
+			y := f.round(header, tweak, i, b, bytesB, bytesD)
+			c := new(big.Int).Add(f.num(a), y)
+			a, b = b, f.str(c.Mod(c, modulus), m)
+		}
+	}
+
+	return a + b, nil
+}
+
+// encrypt encrypts numeral string with tweak.
+func (f *ff1) encrypt(digits string, tweak []byte) (string, error) {
+	return f.transform(digits, tweak, false)
+}
+
+// decrypt decrypts numeral string encrypted with tweak.
+func (f *ff1) decrypt(digits string, tweak []byte) (string, error) {
+	return f.transform(digits, tweak, true)
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package pseudonym
+
+import (
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFF1Samples(t *testing.T) {
+	// FF1 samples published by NIST for SP 800-38G
+	key128 := "2b7e151628aed2a6abf7158809cf4f3c"
+	key192 := key128 + "ef4359d8d580aa4f"
+	key256 := key192 + "7f036d6f04fc6a94"
+
+	for _, test := range []struct {
+		key        string
+		radix      int
+		tweak      string
+		plaintext  string
+		ciphertext string
+	}{
+		{key128, 10, "", "0123456789", "2433477484"},
+		{key128, 10, "39383736353433323130", "0123456789", "6124200773"},
+		{key128, 36, "3737373770717273373737", "0123456789abcdefghi", "a9tv40mll9kdu509eum"},
+		{key192, 10, "", "0123456789", "2830668132"},
+		{key192, 10, "39383736353433323130", "0123456789", "2496655549"},
+		{key192, 36, "3737373770717273373737", "0123456789abcdefghi", "xbj3kv35jrawxv32ysr"},
+		{key256, 10, "", "0123456789", "6657667009"},
+		{key256, 10, "39383736353433323130", "0123456789", "1001623463"},
+		{key256, 36, "3737373770717273373737", "0123456789abcdefghi", "xs8a0azh2avyalyzuwd"},
+	} {
+		key, _ := hex.DecodeString(test.key)
+		tweak, _ := hex.DecodeString(test.tweak)
+
+		f, err := newFF1(key, test.radix)
+		if err != nil {
+			t.Fatalf("Failed to create FF1")
+			return
+		}
+
+		ciphertext, err := f.encrypt(test.plaintext, tweak)
+		if err != nil {
+			t.Fatalf("Failed to encrypt %s", test.plaintext)
+			return
+		}
+		assert.Equal(t, test.ciphertext, ciphertext, test.key)
+
+		plaintext, err := f.decrypt(ciphertext, tweak)
+		if err != nil {
+			t.Fatalf("Failed to decrypt %s", ciphertext)
+			return
+		}
+		assert.Equal(t, test.plaintext, plaintext, test.key)
+	}
+}
+
+func TestFF1Invalid(t *testing.T) {
+	key, _ := hex.DecodeString("2b7e151628aed2a6abf7158809cf4f3c")
+
+	for _, radix := range []int{0, 1, 37} {
+		_, err := newFF1(key, radix)
+		assert.Error(t, err, radix)
+	}
+
+	_, err := newFF1(key[:15], 10)
+	assert.Error(t, err)
+
+	f, _ := newFF1(key, 10)
+	for _, input := range []string{"", "1", "12345", "012345678a", "01234-6789"} {
+		_, err := f.encrypt(input, nil)
+		assert.Error(t, err, input)
+	}
+}
@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package pseudonym provides tools for pseudonymizing urn:dev device identifiers, see RFC 9039 Section 6 on privacy considerations.
+package pseudonym
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+// Subtype is the otherbody subtype of pseudonyms produced by KeySet.
+const Subtype = "pseudo"
+
+const KeyIDRegEx = "^[A-Za-z0-9\\.]+$"
+
+// MinSecretLength is the minimum accepted length of HMAC secrets in bytes.
+const MinSecretLength = 16
+
+// hashLength is the number of HMAC-SHA256 output bytes included in pseudonyms.
+const hashLength = 16
+
+// Key captures single pseudonymization key.
+type Key struct {
+	// ID identifies the key inside pseudonyms. ID is not secret.
+	ID string
+	// Secret is the HMAC-SHA256 key.
+	Secret []byte
+}
+
+// KeySet holds current pseudonymization key and previous keys still accepted by Verify.
+type KeySet struct {
+	keys []Key
+}
+
+func isValidKeyID(name string) bool {
+	match, _ := regexp.MatchString(KeyIDRegEx, name)
+
+	return match
+}
+
+// NewKeySet creates key set where current key is used for creating pseudonyms and both current and previous keys are accepted when verifying them. An error is returned if key ID is not valid or is used twice, or if secret is shorter than MinSecretLength.
+func NewKeySet(current Key, previous ...Key) (*KeySet, error) {
+	out := &KeySet{}
+	ids := map[string]bool{}
+
+	for _, key := range append([]Key{current}, previous...) {
+		if !isValidKeyID(key.ID) || ids[key.ID] {
+			return nil, errors.New("invalid input (key id)")
+		}
+
+		if len(key.Secret) < MinSecretLength {
+			return nil, errors.New("invalid input (key secret)")
+		}
+
+		ids[key.ID] = true
+		out.keys = append(out.keys, Key{ID: key.ID, Secret: append([]byte{}, key.Secret...)})
+	}
+
+	return out, nil
+}
+
+// canonicalName returns FullName with lower case "urn:dev:" prefix.
+func canonicalName(devUrn rfc9039.UrnDev) string {
+	return rfc9039.UrnDevPrefix + devUrn.FullName[len(rfc9039.UrnDevPrefix):]
+}
+
+func (k *KeySet) pseudonymize(key Key, devUrn rfc9039.UrnDev, keepOrganization bool) (rfc9039.UrnDev, error) {
+	parsed, err := rfc9039.Parse(devUrn.FullName)
+	if err != nil {
+		return rfc9039.UrnDev{}, err
+	}
+
+	mac := hmac.New(sha256.New, key.Secret)
+	mac.Write([]byte(canonicalName(parsed)))
+	hash := hex.EncodeToString(mac.Sum(nil)[:hashLength])
+
+	sections := []string{Subtype, key.ID, parsed.Subtype}
+	if keepOrganization && parsed.Organization != "" {
+		sections = append(sections, parsed.Organization)
+	}
+	sections = append(sections, hash)
+
+	return rfc9039.Parse(rfc9039.UrnDevPrefix + strings.Join(sections, ":"))
+}
+
+// Pseudonymize maps devUrn to a stable opaque otherbody urn:dev using the current key: "urn:dev:pseudo:<key id>:<subtype>:<hash>", where hash is truncated HMAC-SHA256 of the urn:dev, including its component part, with lower case "urn:dev:" prefix. When keepOrganization is set organization of "org", "os" and "ops" is kept before the hash, e.g. "urn:dev:pseudo:k1:ops:32473:<hash>", so that pseudonyms can still be grouped by PEN. The same input produces the same pseudonym for as long as the current key is not changed. An error is returned if FullName is not a valid urn:dev or if key set is empty, i.e. not created with NewKeySet.
+func (k *KeySet) Pseudonymize(devUrn rfc9039.UrnDev, keepOrganization bool) (rfc9039.UrnDev, error) {
+	if k == nil || len(k.keys) == 0 {
+		return rfc9039.UrnDev{}, errors.New("invalid input (empty key set)")
+	}
+
+	return k.pseudonymize(k.keys[0], devUrn, keepOrganization)
+}
+
+// Verify checks whether pseudonym was created from devUrn with any key of the key set.
+func (k *KeySet) Verify(pseudonym rfc9039.UrnDev, devUrn rfc9039.UrnDev) bool {
+	if k == nil || pseudonym.Subtype != Subtype || len(pseudonym.Identifier) < 3 || len(pseudonym.Component) > 0 {
+		return false
+	}
+
+	keepOrganization := len(pseudonym.Identifier) == 4
+
+	for _, key := range k.keys {
+		if key.ID != pseudonym.Identifier[0] {
+			continue
+		}
+
+		expected, err := k.pseudonymize(key, devUrn, keepOrganization)
+		if err != nil {
+			return false
+		}
+
+		return hmac.Equal([]byte(expected.FullName[len(rfc9039.UrnDevPrefix):]), []byte(pseudonym.FullName[len(rfc9039.UrnDevPrefix):]))
+	}
+
+	return false
+}
+
+// Cipher provides reversible format preserving pseudonymization of "mac" and "ow" urn:dev names.
+type Cipher struct {
+	ff1 *ff1
+}
+
+// NewCipher creates Cipher using AES key of 16, 24 or 32 bytes.
+func NewCipher(key []byte) (*Cipher, error) {
+	f, err := newFF1(key, 16)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cipher{ff1: f}, nil
+}
+
+// address splits "mac" or "ow" urn:dev into subtype, address as 16 hex digits and the rest of the name following the address.
+func address(devUrn rfc9039.UrnDev) (string, string, string, error) {
+	parsed, err := rfc9039.Parse(devUrn.FullName)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	if parsed.Subtype != "mac" && parsed.Subtype != "ow" {
+		return "", "", "", errors.New("invalid input (subtype)")
+	}
+
+	start := len(rfc9039.UrnDevPrefix) + len(parsed.Subtype) + 1
+
+	return parsed.Subtype, parsed.Eui64Identifier + parsed.OwIdentifier, parsed.FullName[start+16:], nil
+}
+
+func (c *Cipher) transform(devUrn rfc9039.UrnDev, decrypt bool) (rfc9039.UrnDev, error) {
+	subtype, value, rest, err := address(devUrn)
+	if err != nil {
+		return rfc9039.UrnDev{}, err
+	}
+
+	// Subtype is the tweak, so equal addresses of "mac" and "ow" are encrypted differently
+	if decrypt {
+		value, err = c.ff1.decrypt(value, []byte(subtype))
+	} else {
+		value, err = c.ff1.encrypt(value, []byte(subtype))
+	}
+	if err != nil {
+		return rfc9039.UrnDev{}, err
+	}
+
+	return rfc9039.Parse(rfc9039.UrnDevPrefix + subtype + ":" + value + rest)
+}
+
+// Encrypt replaces EUI-64 of "mac" or 1-Wire address of "ow" urn:dev with its encryption, which is again 16 hex digits. Encryption is FF1 format preserving encryption of NIST SP 800-38G with AES, radix 16 and subtype as the tweak. Identifiers and components following the address are kept as is. An error is returned for other subtypes or if FullName is not a valid urn:dev.
+func (c *Cipher) Encrypt(devUrn rfc9039.UrnDev) (rfc9039.UrnDev, error) {
+	return c.transform(devUrn, false)
+}
+
+// Decrypt reverses Encrypt.
+func (c *Cipher) Decrypt(devUrn rfc9039.UrnDev) (rfc9039.UrnDev, error) {
+	return c.transform(devUrn, true)
+}
@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package pseudonym
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+var testKey1 = Key{ID: "k1", Secret: bytes.Repeat([]byte{0x01}, 32)}
+var testKey2 = Key{ID: "k2", Secret: bytes.Repeat([]byte{0x02}, 32)}
+
+func ExampleKeySet_Pseudonymize() {
+	keys, _ := NewKeySet(Key{ID: "k1", Secret: []byte("0123456789abcdef")})
+	devUrn, _ := rfc9039.Parse("urn:dev:ops:32473-Refrigerator-5002")
+	pseudonym, _ := keys.Pseudonymize(devUrn, true)
+	fmt.Println(pseudonym.Subtype)
+	fmt.Println(pseudonym.Identifier[:3])
+	// Output: pseudo
+	// [k1 ops 32473]
+}
+
+func ExampleCipher_Encrypt() {
+	c, _ := NewCipher([]byte("0123456789abcdef"))
+	devUrn, _ := rfc9039.Parse("urn:dev:mac:0024befffe804ff1_eth0")
+	encrypted, _ := c.Encrypt(devUrn)
+	decrypted, _ := c.Decrypt(encrypted)
+	fmt.Println(len(encrypted.Eui64Identifier), encrypted.Component)
+	fmt.Println(decrypted.FullName)
+	// Output: 16 [eth0]
+	// urn:dev:mac:0024befffe804ff1_eth0
+}
+
+func TestPseudonymize(t *testing.T) {
+	keys, err := NewKeySet(testKey1)
+	if err != nil {
+		t.Fatalf("Failed to create key set")
+		return
+	}
+
+	devUrn, _ := rfc9039.Parse("urn:dev:mac:0024befffe804ff1")
+
+	value, err := keys.Pseudonymize(devUrn, true)
+	if err != nil {
+		t.Fatalf("Failed to pseudonymize")
+		return
+	}
+	assert.Equal(t, Subtype, value.Subtype)
+	assert.Equal(t, 3, len(value.Identifier))
+	assert.Equal(t, "k1", value.Identifier[0])
+	assert.Equal(t, "mac", value.Identifier[1])
+	assert.Regexp(t, "^[0-9a-f]{32}$", value.Identifier[2])
+	assert.NotContains(t, value.FullName, "0024befffe804ff1")
+
+	// Stable and independent of urn:dev prefix case
+	again, _ := keys.Pseudonymize(rfc9039.UrnDev{FullName: "URN:DEV:mac:0024befffe804ff1"}, false)
+	assert.Equal(t, value, again)
+
+	other, _ := keys.Pseudonymize(rfc9039.UrnDev{FullName: "urn:dev:mac:0024befffe804ff1_eth0"}, false)
+	assert.NotEqual(t, value.FullName, other.FullName)
+
+	ops, _ := keys.Pseudonymize(rfc9039.UrnDev{FullName: "urn:dev:ops:32473-Refrigerator-5002"}, false)
+	assert.Equal(t, []string{"k1", "ops"}, ops.Identifier[:2])
+	assert.Equal(t, 3, len(ops.Identifier))
+
+	_, err = keys.Pseudonymize(rfc9039.UrnDev{FullName: "urn:dev:mac:0024befffe804ff"}, false)
+	assert.Error(t, err)
+}
+
+func TestPseudonymizeEmptyKeySet(t *testing.T) {
+	devUrn, _ := rfc9039.Parse("urn:dev:mac:0024befffe804ff1")
+
+	var nilKeys *KeySet
+	for _, keys := range []*KeySet{{}, nilKeys} {
+		value, err := keys.Pseudonymize(devUrn, false)
+		assert.Error(t, err)
+		assert.Equal(t, rfc9039.UrnDev{}, value)
+		assert.False(t, keys.Verify(rfc9039.UrnDev{FullName: "urn:dev:pseudo:k1:mac:00"}, devUrn))
+	}
+}
+
+func TestVerifyRotation(t *testing.T) {
+	oldKeys, _ := NewKeySet(testKey1)
+	newKeys, _ := NewKeySet(testKey2, testKey1)
+	onlyNewKeys, _ := NewKeySet(testKey2)
+
+	devUrn, _ := rfc9039.Parse("urn:dev:ops:32473-Refrigerator-5002")
+	otherUrn, _ := rfc9039.Parse("urn:dev:ops:32473-Refrigerator-5003")
+
+	oldValue, _ := oldKeys.Pseudonymize(devUrn, true)
+	newValue, _ := newKeys.Pseudonymize(devUrn, true)
+	assert.Equal(t, "k1", oldValue.Identifier[0])
+	assert.Equal(t, "k2", newValue.Identifier[0])
+	assert.NotEqual(t, oldValue.Identifier[3], newValue.Identifier[3])
+
+	assert.True(t, newKeys.Verify(oldValue, devUrn))
+	assert.True(t, newKeys.Verify(newValue, devUrn))
+	assert.False(t, newKeys.Verify(newValue, otherUrn))
+	assert.False(t, onlyNewKeys.Verify(oldValue, devUrn))
+	assert.False(t, oldKeys.Verify(devUrn, devUrn))
+
+	shortValue, _ := newKeys.Pseudonymize(devUrn, false)
+	assert.True(t, newKeys.Verify(shortValue, devUrn))
+}
+
+func TestNewKeySetInvalid(t *testing.T) {
+	for _, keys := range [][]Key{
+		{{ID: "", Secret: testKey1.Secret}},
+		{{ID: "k:1", Secret: testKey1.Secret}},
+		{{ID: "k1", Secret: []byte("short")}},
+		{testKey1, testKey1},
+	} {
+		value, err := NewKeySet(keys[0], keys[1:]...)
+		assert.Error(t, err)
+		assert.Nil(t, value)
+	}
+}
+
+func TestCipher(t *testing.T) {
+	c, err := NewCipher(bytes.Repeat([]byte{0x2b}, 16))
+	if err != nil {
+		t.Fatalf("Failed to create cipher")
+		return
+	}
+
+	for _, name := range []string{
+		"urn:dev:mac:0024befffe804ff1",
+		"urn:dev:mac:0000000000000000",
+		"urn:dev:mac:ffffffffffffffff:a:b_eth0",
+		"urn:dev:ow:10e2073a01080063",
+		"urn:dev:ow:264437f5000000ed_humidity",
+	} {
+		devUrn, _ := rfc9039.Parse(name)
+
+		encrypted, err := c.Encrypt(devUrn)
+		if err != nil {
+			t.Fatalf("Failed to encrypt %s", name)
+			return
+		}
+		assert.Equal(t, devUrn.Subtype, encrypted.Subtype)
+		assert.Equal(t, devUrn.Identifier, encrypted.Identifier)
+		assert.Equal(t, devUrn.Component, encrypted.Component)
+		assert.NotEqual(t, devUrn.FullName, encrypted.FullName)
+
+		decrypted, err := c.Decrypt(encrypted)
+		if err != nil {
+			t.Fatalf("Failed to decrypt %s", name)
+			return
+		}
+		assert.Equal(t, devUrn, decrypted)
+	}
+
+	// Address is encrypted with FF1 using subtype as tweak
+	mac, _ := c.Encrypt(rfc9039.UrnDev{FullName: "urn:dev:mac:10e2073a01080063"})
+	ow, _ := c.Encrypt(rfc9039.UrnDev{FullName: "urn:dev:ow:10e2073a01080063"})
+	assert.NotEqual(t, mac.Eui64Identifier, ow.OwIdentifier)
+
+	f, _ := newFF1(bytes.Repeat([]byte{0x2b}, 16), 16)
+	expected, _ := f.encrypt("10e2073a01080063", []byte("mac"))
+	assert.Equal(t, expected, mac.Eui64Identifier)
+	expected, _ = f.encrypt("10e2073a01080063", []byte("ow"))
+	assert.Equal(t, expected, ow.OwIdentifier)
+
+	// Different key gives different result
+	other, _ := NewCipher(bytes.Repeat([]byte{0x2c}, 16))
+	otherMac, _ := other.Encrypt(rfc9039.UrnDev{FullName: "urn:dev:mac:10e2073a01080063"})
+	assert.NotEqual(t, mac.Eui64Identifier, otherMac.Eui64Identifier)
+}
+
+func TestCipherInvalid(t *testing.T) {
+	_, err := NewCipher([]byte("short"))
+	assert.Error(t, err)
+
+	c, _ := NewCipher(bytes.Repeat([]byte{0x2b}, 16))
+	for _, name := range []string{
+		"urn:dev:ops:32473-Refrigerator-5002",
+		"urn:dev:mac:0024befffe804ff",
+		"",
+	} {
+		value, err := c.Encrypt(rfc9039.UrnDev{FullName: name})
+		assert.Error(t, err, name)
+		assert.Equal(t, rfc9039.UrnDev{}, value)
+	}
+}
@@ -0,0 +1,240 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rfc9039
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strconv"
+)
+
+// Subtype tag bytes used in the binary form.
+const (
+	BinaryTagMac   = 0x01
+	BinaryTagOw    = 0x02
+	BinaryTagOrg   = 0x03
+	BinaryTagOs    = 0x04
+	BinaryTagOps   = 0x05
+	BinaryTagOther = 0x06
+)
+
+var binaryTags = map[string]byte{"mac": BinaryTagMac, "ow": BinaryTagOw, "org": BinaryTagOrg, "os": BinaryTagOs, "ops": BinaryTagOps}
+
+// Markers of the binary form. Terminator ends strings and lists and sorts before any character allowed in urn:dev, list item marker precedes each list item.
+const (
+	binaryTerminator = 0x00
+	binaryListItem   = 0x01
+)
+
+// appendVarint appends order preserving varint: values up to 240 are a single byte, up to 2287 two bytes starting with 241 to 248, up to 67823 three bytes starting with 249, and larger values a byte from 250 to 255 giving the length 3 to 8 followed by the value in big endian. Encodings of larger values sort after encodings of smaller ones.
+func appendVarint(dst []byte, value uint64) []byte {
+	switch {
+	case value <= 240:
+		return append(dst, byte(value))
+	case value <= 2287:
+		return append(dst, byte(241+(value-240)/256), byte((value-240)%256))
+	case value <= 67823:
+		return append(dst, 249, byte((value-2288)/256), byte((value-2288)%256))
+	}
+
+	data := bytes.TrimLeft(binary.BigEndian.AppendUint64(nil, value), "\x00")
+
+	return append(append(dst, byte(247+len(data))), data...)
+}
+
+// readVarint decodes varint written by appendVarint. Only the shortest encoding of each value is accepted to keep the encoding unique.
+func readVarint(data []byte) (uint64, []byte, bool) {
+	if len(data) == 0 {
+		return 0, nil, false
+	}
+
+	switch first := data[0]; {
+	case first <= 240:
+		return uint64(first), data[1:], true
+
+	case first <= 248:
+		if len(data) < 2 {
+			return 0, nil, false
+		}
+		value := 240 + uint64(first-241)*256 + uint64(data[1])
+		return value, data[2:], value > 240
+
+	case first == 249:
+		if len(data) < 3 {
+			return 0, nil, false
+		}
+		return 2288 + uint64(data[1])*256 + uint64(data[2]), data[3:], true
+
+	default:
+		length := int(first) - 247
+		if len(data) < 1+length || data[1] == 0 {
+			return 0, nil, false
+		}
+		value := binary.BigEndian.Uint64(append(make([]byte, 8-length), data[1:1+length]...))
+		return value, data[1+length:], value > 67823
+	}
+}
+
+// MarshalBinary encodes urn:dev into deterministic binary form suitable for sortable storage keys. Encoding starts with a subtype tag byte, see BinaryTagMac and others, and for other subtypes continues with the subtype string. EUI-64 and 1-Wire addresses follow as 8 bytes and organizations as order preserving varint of 1 to 9 bytes, where values up to 240 take a single byte and the first byte tells the length of larger values. Product, serial and subtype strings are terminated with a zero byte, and identifiers and components are written as lists where each item is preceded by 0x01 and the list is terminated with a zero byte. Strings are terminated instead of prefixed with their length, as a length prefix orders shorter strings first and would not preserve the byte by byte ordering of Compare. For two urn:dev names of the same subtype byte order of the encodings matches canonical ordering: organization compared numerically, then product, serial, identifiers and components compared as strings and lists of strings. An error is returned if FullName is not a valid urn:dev.
+func (u UrnDev) MarshalBinary() ([]byte, error) {
+	devUrn, err := Parse(u.FullName)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, ok := binaryTags[devUrn.Subtype]
+	if !ok {
+		tag = BinaryTagOther
+	}
+
+	out := []byte{tag}
+
+	switch tag {
+	case BinaryTagMac, BinaryTagOw:
+		address, err := hex.DecodeString(devUrn.Eui64Identifier + devUrn.OwIdentifier)
+		if err != nil {
+			return nil, errors.New("invalid input (address)")
+		}
+		out = append(out, address...)
+
+	case BinaryTagOrg, BinaryTagOs, BinaryTagOps:
+		pen, err := strconv.ParseUint(devUrn.Organization, 10, 64)
+		if err != nil {
+			return nil, errors.New("invalid input (organization)")
+		}
+
+		out = appendVarint(out, pen)
+
+		if tag == BinaryTagOps {
+			out = append(append(out, devUrn.Product...), binaryTerminator)
+		}
+		if tag != BinaryTagOrg {
+			out = append(append(out, devUrn.Serial...), binaryTerminator)
+		}
+
+	default:
+		out = append(append(out, devUrn.Subtype...), binaryTerminator)
+	}
+
+	out = appendBinaryList(out, devUrn.Identifier)
+	out = appendBinaryList(out, devUrn.Component)
+
+	return out, nil
+}
+
+// UnmarshalBinary decodes urn:dev from the form produced by MarshalBinary. Decoded urn:dev is validated with Parse and in case of an error the receiver is not modified.
+func (u *UrnDev) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("invalid input (empty)")
+	}
+
+	devUrn := UrnDev{}
+	tag, rest := data[0], data[1:]
+
+	var ok bool
+
+	switch tag {
+	case BinaryTagMac, BinaryTagOw:
+		ok = len(rest) >= 8
+		if ok {
+			if tag == BinaryTagMac {
+				devUrn.Subtype, devUrn.Eui64Identifier = "mac", hex.EncodeToString(rest[:8])
+			} else {
+				devUrn.Subtype, devUrn.OwIdentifier = "ow", hex.EncodeToString(rest[:8])
+			}
+			rest = rest[8:]
+		}
+
+	case BinaryTagOrg, BinaryTagOs, BinaryTagOps:
+		for subtype, value := range binaryTags {
+			if value == tag {
+				devUrn.Subtype = subtype
+			}
+		}
+
+		var pen uint64
+		pen, rest, ok = readVarint(rest)
+		devUrn.Organization = strconv.FormatUint(pen, 10)
+
+		if ok && tag == BinaryTagOps {
+			devUrn.Product, rest, ok = readBinaryString(rest)
+		}
+		if ok && tag != BinaryTagOrg {
+			devUrn.Serial, rest, ok = readBinaryString(rest)
+		}
+
+	case BinaryTagOther:
+		devUrn.Subtype, rest, ok = readBinaryString(rest)
+		// Subtypes with own tag are not accepted as other subtype to keep the encoding unique
+		_, known := binaryTags[devUrn.Subtype]
+		ok = ok && !known
+	}
+
+	if !ok {
+		return errors.New("invalid input (binary)")
+	}
+
+	if devUrn.Identifier, rest, ok = readBinaryList(rest); !ok {
+		return errors.New("invalid input (identifier)")
+	}
+
+	if devUrn.Component, rest, ok = readBinaryList(rest); !ok {
+		return errors.New("invalid input (componentpart)")
+	}
+
+	if len(rest) > 0 {
+		return errors.New("invalid input (trailing bytes)")
+	}
+
+	value, err := Parse(formatName(devUrn))
+	if err != nil {
+		return err
+	}
+
+	// Fields containing separators could form a valid name with different fields
+	if encoded, err := value.MarshalBinary(); err != nil || !bytes.Equal(encoded, data) {
+		return errors.New("invalid input (binary)")
+	}
+
+	*u = value
+
+	return nil
+}
+
+func appendBinaryList(dst []byte, values []string) []byte {
+	for _, value := range values {
+		dst = append(append(append(dst, binaryListItem), value...), binaryTerminator)
+	}
+
+	return append(dst, binaryTerminator)
+}
+
+func readBinaryString(data []byte) (string, []byte, bool) {
+	index := bytes.IndexByte(data, binaryTerminator)
+	if index < 0 {
+		return "", nil, false
+	}
+
+	return string(data[:index]), data[index+1:], true
+}
+
+func readBinaryList(data []byte) ([]string, []byte, bool) {
+	out := []string{}
+
+	for len(data) > 0 && data[0] == binaryListItem {
+		var value string
+		var ok bool
+		if value, data, ok = readBinaryString(data[1:]); !ok {
+			return nil, nil, false
+		}
+		out = append(out, value)
+	}
+
+	if len(data) == 0 || data[0] != binaryTerminator {
+		return nil, nil, false
+	}
+
+	return out, data[1:], true
+}
@@ -0,0 +1,198 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rfc9039
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func ExampleUrnDev_MarshalBinary() {
+	devUrn, _ := Parse("urn:dev:ops:32473-Refrigerator-5002_compressor")
+	data, _ := devUrn.MarshalBinary()
+	fmt.Println(hex.EncodeToString(data))
+	// Output: 05f975e9526566726967657261746f720035303032000001636f6d70726573736f720000
+}
+
+func TestMarshalBinary(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		output string
+	}{
+		{"urn:dev:mac:0024befffe804ff1", "010024befffe804ff10000"},
+		{"urn:dev:ow:10e2073a01080063_humidity", "0210e2073a01080063000168756d69646974790000"},
+		{"urn:dev:org:4-foo:bar", "030401666f6f0001626172000000"},
+		{"urn:dev:os:32473-12-34", "04f975e931322d3334000000"},
+		{"urn:dev:example:foo", "066578616d706c650001666f6f000000"},
+	} {
+		devUrn, err := Parse(test.name)
+		if err != nil {
+			t.Fatalf("Failed to parse %s", test.name)
+			return
+		}
+
+		data, err := devUrn.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Failed to marshal %s", test.name)
+			return
+		}
+		assert.Equal(t, test.output, hex.EncodeToString(data), test.name)
+
+		value := UrnDev{}
+		err = value.UnmarshalBinary(data)
+		if err != nil {
+			t.Fatalf("Failed to unmarshal %s: %v", test.name, err)
+			return
+		}
+		assert.Equal(t, devUrn, value, test.name)
+	}
+}
+
+func TestMarshalBinaryOrdering(t *testing.T) {
+	// Names of each subtype in canonical order
+	for _, names := range [][]string{
+		{
+			"urn:dev:mac:0024befffe804ff1",
+			"urn:dev:mac:0024befffe804ff1_eth0",
+			"urn:dev:mac:0024befffe804ff1:a:b",
+			"urn:dev:mac:0024befffe804ff2",
+			"urn:dev:mac:a024befffe804ff1",
+		},
+		{
+			"urn:dev:org:4-foo",
+			"urn:dev:org:240-foo",
+			"urn:dev:org:241-foo",
+			"urn:dev:org:255-foo",
+			"urn:dev:org:256-foo",
+			"urn:dev:org:2287-foo",
+			"urn:dev:org:2288-foo",
+			"urn:dev:org:32473-foo",
+			"urn:dev:org:32473-foo_a",
+			"urn:dev:org:32473-foo_a_b",
+			"urn:dev:org:32473-foo_b",
+			"urn:dev:org:32473-foo:bar",
+			"urn:dev:org:32473-foo.bar",
+			"urn:dev:org:32473-foobar",
+			"urn:dev:org:67823-foo",
+			"urn:dev:org:67824-foo",
+			"urn:dev:org:16777215-foo",
+			"urn:dev:org:16777216-foo",
+			"urn:dev:org:18446744073709551615-a",
+		},
+		{
+			"urn:dev:ops:4-B-1",
+			"urn:dev:ops:32473-A-1",
+			"urn:dev:ops:32473-A-10",
+			"urn:dev:ops:32473-A-2",
+			"urn:dev:ops:32473-A-2_c",
+			"urn:dev:ops:32473-A-2:x",
+			"urn:dev:ops:32473-A-2:x_c",
+			"urn:dev:ops:32473-A.b-1",
+			"urn:dev:ops:32473-B-0",
+		},
+		{
+			"urn:dev:os:32473-5",
+			"urn:dev:os:32473-5-1",
+			"urn:dev:os:32473-50",
+		},
+	} {
+		var previous []byte
+		var previousUrn UrnDev
+		for _, name := range names {
+			devUrn, err := Parse(name)
+			if err != nil {
+				t.Fatalf("Failed to parse %s", name)
+				return
+			}
+
+			data, err := devUrn.MarshalBinary()
+			if err != nil {
+				t.Fatalf("Failed to marshal %s", name)
+				return
+			}
+
+			if previous != nil {
+				assert.Equal(t, 1, bytes.Compare(data, previous), name)
+				assert.Equal(t, 1, Compare(devUrn, previousUrn), name)
+			}
+			previous, previousUrn = data, devUrn
+		}
+	}
+}
+
+func TestMarshalBinaryInvalid(t *testing.T) {
+	devUrn := UrnDev{FullName: "urn:dev:ow:10e2073a0108006"}
+
+	_, err := devUrn.MarshalBinary()
+	assert.Error(t, err)
+
+	devUrn = UrnDev{FullName: "urn:dev:ops:123456789012345678901234567890-a-1"}
+
+	_, err = devUrn.MarshalBinary()
+	assert.Error(t, err)
+}
+
+func TestUnmarshalBinaryInvalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"00",
+		"07",
+		"010024befffe804f",
+		"010024befffe804ff1",
+		"010024befffe804ff100",
+		"010024befffe804ff1000000",
+		"010024befffe804ff101610000",
+		"03",
+		"03f9",
+		"03fb0102",
+		"03f10001666f6f000000",
+		"03fa00ffff01666f6f000000",
+		"03fa0108ef01666f6f000000",
+		"03f975e9",
+		"03f975e900",
+		"03f975e90000",
+		"04f975e93500",
+		"04f975e9353a36000000",
+		"05f975e941003100",
+		"05f975e9412d420031000000",
+		"066d61630001300000",
+		"0601300000",
+		"06",
+		"0600000000",
+		"06657800015f000000",
+	} {
+		data, err := hex.DecodeString(input)
+		if err != nil {
+			t.Fatalf("Failed to decode %s", input)
+			return
+		}
+
+		value := UrnDev{FullName: "unchanged"}
+		err = value.UnmarshalBinary(data)
+		assert.Error(t, err, input)
+		assert.Equal(t, UrnDev{FullName: "unchanged"}, value, input)
+	}
+}
+
+func TestVarint(t *testing.T) {
+	var previous []byte
+	for _, value := range []uint64{0, 1, 240, 241, 255, 256, 2287, 2288, 32473, 67823, 67824, 16777215, 16777216, 1<<32 - 1, 1 << 32, 1<<64 - 1} {
+		data := appendVarint(nil, value)
+
+		decoded, rest, ok := readVarint(append(data, 0xaa))
+		assert.True(t, ok, value)
+		assert.Equal(t, value, decoded)
+		assert.Equal(t, []byte{0xaa}, rest)
+
+		if previous != nil {
+			assert.Equal(t, 1, bytes.Compare(data, previous), value)
+		}
+		previous = data
+	}
+
+	assert.Equal(t, []byte{0xf9, 0x75, 0xe9}, appendVarint(nil, 32473))
+	assert.Equal(t, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, appendVarint(nil, 1<<64-1))
+}
@@ -0,0 +1,270 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rfc9039
+
+import (
+	"encoding/hex"
+	"errors"
+	"strconv"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/internal/cbor"
+)
+
+// Subtype codes used in the compact CBOR form. Other subtypes are encoded as text strings.
+const (
+	CBORSubtypeMac = 0
+	CBORSubtypeOw  = 1
+	CBORSubtypeOrg = 2
+	CBORSubtypeOs  = 3
+	CBORSubtypeOps = 4
+)
+
+var cborSubtypes = []string{"mac", "ow", "org", "os", "ops"}
+
+// cborFieldCount is the number of subtype specific fields following the subtype in the compact CBOR form.
+var cborFieldCount = map[string]int{"mac": 1, "ow": 1, "org": 1, "os": 2, "ops": 3}
+
+// MarshalCBOR encodes urn:dev as a text string tagged with URI tag 32. An error is returned if FullName is not a valid urn:dev.
+func (u UrnDev) MarshalCBOR() ([]byte, error) {
+	if _, err := Parse(u.FullName); err != nil {
+		return nil, err
+	}
+
+	return cbor.AppendText(cbor.AppendTag(nil, cbor.TagURI), u.FullName), nil
+}
+
+// MarshalCompactCBOR encodes urn:dev as an array of subtype followed by subtype specific fields, identifiers array and components array. Subtype is encoded as an integer code for "mac", "ow", "org", "os" and "ops", see CBORSubtypeMac and others, and as a text string for other subtypes. EUI-64 and 1-Wire addresses are encoded as 8 byte byte strings, organizations as integers and product and serial as text strings. Identifiers and components arrays are left out when they are empty and nothing follows them, e.g. "urn:dev:ops:32473-Refrigerator-5002" is encoded as [4, 32473, "Refrigerator", "5002"]. Note that for "org" the first identifier is part of the identifiers array. An error is returned if FullName is not a valid urn:dev or organization is larger than math.MaxInt64, which does not decode.
+func (u UrnDev) MarshalCompactCBOR() ([]byte, error) {
+	devUrn, err := Parse(u.FullName)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []byte
+	count := cborFieldCount[devUrn.Subtype]
+
+	switch devUrn.Subtype {
+	case "mac", "ow":
+		address, err := hex.DecodeString(devUrn.Eui64Identifier + devUrn.OwIdentifier)
+		if err != nil {
+			return nil, errors.New("invalid input (address)")
+		}
+		fields = cbor.AppendBytes(fields, address)
+
+	case "org", "os", "ops":
+		// Decoding accepts integers up to math.MaxInt64 only
+		pen, err := strconv.ParseInt(devUrn.Organization, 10, 64)
+		if err != nil {
+			return nil, errors.New("invalid input (organization)")
+		}
+		fields = cbor.AppendInt(fields, pen)
+
+		if devUrn.Subtype == "ops" {
+			fields = cbor.AppendText(fields, devUrn.Product)
+		}
+		if devUrn.Subtype != "org" {
+			fields = cbor.AppendText(fields, devUrn.Serial)
+		}
+	}
+
+	if len(devUrn.Component) > 0 {
+		count += 2
+	} else if len(devUrn.Identifier) > 0 {
+		count += 1
+	}
+
+	out := cbor.AppendArray(nil, 1+count)
+
+	if index := subtypeCode(devUrn.Subtype); index >= 0 {
+		out = cbor.AppendUint(out, uint64(index))
+	} else {
+		out = cbor.AppendText(out, devUrn.Subtype)
+	}
+
+	out = append(out, fields...)
+
+	if count > cborFieldCount[devUrn.Subtype] {
+		out = appendTextArray(out, devUrn.Identifier)
+	}
+
+	if len(devUrn.Component) > 0 {
+		out = appendTextArray(out, devUrn.Component)
+	}
+
+	return out, nil
+}
+
+// UnmarshalCBOR decodes urn:dev from either form produced by MarshalCBOR or MarshalCompactCBOR. Untagged text string is also accepted. Decoded urn:dev is validated with Parse and in case of an error the receiver is not modified.
+func (u *UrnDev) UnmarshalCBOR(data []byte) error {
+	value, err := cbor.Decode(data)
+	if err != nil {
+		return err
+	}
+
+	var name string
+
+	switch item := value.(type) {
+	case cbor.Tag:
+		text, ok := item.Content.(string)
+		if item.Number != cbor.TagURI || !ok {
+			return errors.New("invalid input (tag)")
+		}
+		name = text
+
+	case string:
+		name = item
+
+	case []any:
+		name, err = compactName(item)
+		if err != nil {
+			return err
+		}
+
+	default:
+		return errors.New("invalid input (cbor)")
+	}
+
+	devUrn, err := Parse(name)
+	if err != nil {
+		return err
+	}
+
+	*u = devUrn
+
+	return nil
+}
+
+func subtypeCode(subtype string) int {
+	for index, value := range cborSubtypes {
+		if value == subtype {
+			return index
+		}
+	}
+
+	return -1
+}
+
+func appendTextArray(dst []byte, values []string) []byte {
+	dst = cbor.AppendArray(dst, len(values))
+	for _, value := range values {
+		dst = cbor.AppendText(dst, value)
+	}
+
+	return dst
+}
+
+func textArray(value any) ([]string, bool) {
+	items, ok := value.([]any)
+	if !ok {
+		return nil, false
+	}
+
+	out := []string{}
+	for _, item := range items {
+		text, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+		out = append(out, text)
+	}
+
+	return out, true
+}
+
+// compactName builds urn:dev string from decoded compact CBOR form. An error is returned if the string does not parse back into the same fields.
+func compactName(items []any) (string, error) {
+	if len(items) == 0 {
+		return "", errors.New("invalid input (subtype)")
+	}
+
+	devUrn := UrnDev{}
+
+	switch subtype := items[0].(type) {
+	case int64:
+		if subtype < 0 || subtype >= int64(len(cborSubtypes)) {
+			return "", errors.New("invalid input (subtype)")
+		}
+		devUrn.Subtype = cborSubtypes[subtype]
+
+	case string:
+		// Subtypes with integer code are not accepted as text to keep the encoding unique
+		if subtypeCode(subtype) >= 0 {
+			return "", errors.New("invalid input (subtype)")
+		}
+		devUrn.Subtype = subtype
+
+	default:
+		return "", errors.New("invalid input (subtype)")
+	}
+
+	count := cborFieldCount[devUrn.Subtype]
+	fields := items[1:]
+	if len(fields) < count || len(fields) > count+2 {
+		return "", errors.New("invalid input (" + devUrn.Subtype + ")")
+	}
+
+	var ok bool
+
+	switch devUrn.Subtype {
+	case "mac", "ow":
+		var address []byte
+		address, ok = fields[0].([]byte)
+		ok = ok && len(address) == 8
+		if devUrn.Subtype == "mac" {
+			devUrn.Eui64Identifier = hex.EncodeToString(address)
+		} else {
+			devUrn.OwIdentifier = hex.EncodeToString(address)
+		}
+
+	case "org", "os", "ops":
+		var pen int64
+		pen, ok = fields[0].(int64)
+		ok = ok && pen > 0
+		devUrn.Organization = strconv.FormatInt(pen, 10)
+
+		var product, serial string
+		productOk, serialOk := true, true
+		if devUrn.Subtype == "ops" {
+			product, productOk = fields[1].(string)
+			serial, serialOk = fields[2].(string)
+		} else if devUrn.Subtype == "os" {
+			serial, serialOk = fields[1].(string)
+		}
+		ok = ok && productOk && serialOk
+		devUrn.Product, devUrn.Serial = product, serial
+
+	default:
+		ok = true
+	}
+
+	if !ok {
+		return "", errors.New("invalid input (" + devUrn.Subtype + ")")
+	}
+
+	devUrn.Identifier, devUrn.Component = []string{}, []string{}
+
+	if len(fields) > count {
+		if devUrn.Identifier, ok = textArray(fields[count]); !ok {
+			return "", errors.New("invalid input (identifier)")
+		}
+	}
+
+	if len(fields) > count+1 {
+		if devUrn.Component, ok = textArray(fields[count+1]); !ok {
+			return "", errors.New("invalid input (componentpart)")
+		}
+	}
+
+	devUrn.FullName = formatName(devUrn)
+
+	// Fields containing separators could form a valid name with different fields
+	value, err := Parse(devUrn.FullName)
+	if err != nil {
+		return "", err
+	}
+	if Compare(value, devUrn) != 0 {
+		return "", errors.New("invalid input (cbor)")
+	}
+
+	return devUrn.FullName, nil
+}
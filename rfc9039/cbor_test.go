@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rfc9039
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func ExampleUrnDev_MarshalCompactCBOR() {
+	devUrn, _ := Parse("urn:dev:mac:0024befffe804ff1")
+	data, _ := devUrn.MarshalCompactCBOR()
+	fmt.Println(hex.EncodeToString(data))
+	// Output: 8200480024befffe804ff1
+}
+
+func TestMarshalCBOR(t *testing.T) {
+	devUrn, _ := Parse("urn:dev:ops:32473-Refrigerator-5002")
+
+	data, err := devUrn.MarshalCBOR()
+	if err != nil {
+		t.Fatalf("Failed to marshal")
+		return
+	}
+	assert.Equal(t, "d8207823"+hex.EncodeToString([]byte("urn:dev:ops:32473-Refrigerator-5002")), hex.EncodeToString(data))
+
+	data, err = devUrn.MarshalCompactCBOR()
+	if err != nil {
+		t.Fatalf("Failed to marshal")
+		return
+	}
+	assert.Equal(t, "8404197ed96c526566726967657261746f726435303032", hex.EncodeToString(data))
+
+	value := UrnDev{}
+	err = value.UnmarshalCBOR(data)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal")
+		return
+	}
+	assert.Equal(t, devUrn, value)
+}
+
+func TestMarshalCBORRoundTrip(t *testing.T) {
+	for _, name := range []string{
+		"urn:dev:mac:0024befffe804ff1",
+		"urn:dev:mac:0024befffe804ff1:a:b_eth0",
+		"urn:dev:ow:10e2073a01080063",
+		"urn:dev:ow:264437f5000000ed_humidity_sub",
+		"urn:dev:org:32473-foo",
+		"urn:dev:org:32473-foo:bar:zoo_component",
+		"urn:dev:os:32473-12-34-56",
+		"urn:dev:os:32473-5002:ident_component",
+		"urn:dev:ops:32473-Refrigerator-5002",
+		"urn:dev:ops:32473-Refrigerator-5002:foo_compressor",
+		"urn:dev:example:foo",
+		"urn:dev:example:foo:bar_baz",
+	} {
+		devUrn, err := Parse(name)
+		if err != nil {
+			t.Fatalf("Failed to parse %s", name)
+			return
+		}
+
+		for _, marshal := range []func() ([]byte, error){devUrn.MarshalCBOR, devUrn.MarshalCompactCBOR} {
+			data, err := marshal()
+			if err != nil {
+				t.Fatalf("Failed to marshal %s", name)
+				return
+			}
+
+			value := UrnDev{}
+			err = value.UnmarshalCBOR(data)
+			if err != nil {
+				t.Fatalf("Failed to unmarshal %s: %v", name, err)
+				return
+			}
+			assert.Equal(t, devUrn, value, name)
+		}
+	}
+}
+
+func TestMarshalCBORInvalid(t *testing.T) {
+	devUrn := UrnDev{FullName: "urn:dev:mac:0024befffe804ff"}
+
+	_, err := devUrn.MarshalCBOR()
+	assert.Error(t, err)
+
+	_, err = devUrn.MarshalCompactCBOR()
+	assert.Error(t, err)
+
+	devUrn = UrnDev{FullName: "urn:dev:os:123456789012345678901234567890-5002"}
+
+	_, err = devUrn.MarshalCompactCBOR()
+	assert.Error(t, err)
+
+	// Compact form decodes organizations up to math.MaxInt64 only
+	devUrn = UrnDev{FullName: "urn:dev:os:9223372036854775808-5002"}
+
+	_, err = devUrn.MarshalCompactCBOR()
+	assert.Error(t, err)
+
+	devUrn, _ = Parse("urn:dev:os:9223372036854775807-5002")
+	data, err := devUrn.MarshalCompactCBOR()
+	if err != nil {
+		t.Fatalf("Failed to marshal %s", devUrn.FullName)
+		return
+	}
+
+	value := UrnDev{}
+	assert.NoError(t, value.UnmarshalCBOR(data))
+	assert.Equal(t, devUrn, value)
+}
+
+func TestUnmarshalCBOR(t *testing.T) {
+	devUrn := UrnDev{}
+
+	// Untagged text string
+	err := devUrn.UnmarshalCBOR(append([]byte{0x78, 0x1b}, "urn:dev:ow:10e2073a01080063"...))
+	assert.NoError(t, err)
+	assert.Equal(t, "10e2073a01080063", devUrn.OwIdentifier)
+
+	// Other subtype with trailing empty components array
+	data, _ := hex.DecodeString("83676578616d706c658163666f6f80")
+	err = devUrn.UnmarshalCBOR(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "urn:dev:example:foo", devUrn.FullName)
+}
+
+func TestUnmarshalCBORInvalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"00",
+		"80",
+		"8105",
+		"8120",
+		"81636d6163",
+		"8100",
+		"82004701020304050607",
+		"82004401020304",
+		"8200480024befffe804ff18161",
+		"820200",
+		"8202197ed9",
+		"8202197ed980",
+		"8202197ed98101",
+		"8203197ed9",
+		"8204197ed96161",
+		"8303197ed963313a78",
+		"8302197ed98163615f62",
+		"8404197ed963612d626131",
+		"83676578616d706c65",
+		"82676578616d706c6580",
+		"82676578616d706c658161",
+		"82676578616d706c6581615f",
+		"d8216161",
+		"d82001",
+		"6a75726e3a6465763a",
+	} {
+		data, err := hex.DecodeString(input)
+		if err != nil {
+			t.Fatalf("Failed to decode %s", input)
+			return
+		}
+
+		value := UrnDev{FullName: "unchanged"}
+		err = value.UnmarshalCBOR(data)
+		assert.Error(t, err, input)
+		assert.Equal(t, UrnDev{FullName: "unchanged"}, value, input)
+	}
+}
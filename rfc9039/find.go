@@ -0,0 +1,282 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rfc9039
+
+import (
+	"encoding/hex"
+	"io"
+	"strings"
+)
+
+// MaxMatchLength limits the length of text Scanner keeps in memory for a single urn:dev candidate.
+const MaxMatchLength = 64 * 1024
+
+// scannerReadSize is the number of bytes Scanner reads at a time.
+const scannerReadSize = 4096
+
+// findPrefixes are the lower case forms of urn:dev prefix searched for in text, as is and URL encoded.
+var findPrefixes = []string{UrnDevPrefix, "urn%3adev%3a"}
+
+// Match captures single urn:dev found in text.
+type Match struct {
+	// Start is the byte offset of the first byte of the urn:dev in text.
+	Start int
+	// End is the byte offset following the last byte of the urn:dev in text. Text between Start and End may contain URL encoded characters and line breaks.
+	End int
+	// UrnDev is the parsed urn:dev with URL encoding decoded and line breaks removed.
+	UrnDev UrnDev
+}
+
+func isNameChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == ':' || c == '_' || c == '.' || c == '-'
+}
+
+func isWordChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// prefixAt checks whether text starts with urn:dev prefix. Partial is set when text ends before the prefix could be fully compared.
+func prefixAt(text []byte) (found bool, partial bool) {
+	for _, prefix := range findPrefixes {
+		n := len(prefix)
+		if len(text) < n {
+			n = len(text)
+		}
+
+		if strings.ToLower(string(text[:n])) != prefix[:n] {
+			continue
+		}
+
+		if n == len(prefix) {
+			return true, false
+		}
+		partial = true
+	}
+
+	return false, partial
+}
+
+// matchAt reads urn:dev candidate starting at start and returns the longest valid urn:dev of it. Incomplete is set when candidate reaches the end of text which is not final.
+func matchAt(text []byte, start int, final bool) (match Match, ok bool, incomplete bool) {
+	var decoded []byte
+	// ends holds text offset following each decoded byte
+	var ends []int
+	// breaks holds decoded lengths at plain line breaks
+	var breaks []int
+	// more is set when following text is needed to decide where candidate ends
+	more := false
+
+	i := start
+
+loop:
+	for i < len(text) {
+		c := text[i]
+
+		switch {
+		case isNameChar(c):
+			decoded = append(decoded, c)
+			i++
+			ends = append(ends, i)
+
+		case c == '%':
+			if i+3 > len(text) {
+				more = true
+				break loop
+			}
+			value, err := hex.DecodeString(string(text[i+1 : i+3]))
+			if err != nil || !isNameChar(value[0]) {
+				break loop
+			}
+			decoded = append(decoded, value[0])
+			i += 3
+			ends = append(ends, i)
+
+		case c == '=' || c == '\\' || c == '\r' || c == '\n':
+			// Line break, soft line break when preceded by "=" or "\"
+			soft := c == '=' || c == '\\'
+			j := i
+			if soft {
+				j++
+			}
+			if j < len(text) && text[j] == '\r' {
+				j++
+			}
+			if j >= len(text) {
+				more = true
+				break loop
+			}
+			if text[j] != '\n' {
+				break loop
+			}
+			j++
+			if !soft {
+				for j < len(text) && (text[j] == ' ' || text[j] == '\t') {
+					j++
+				}
+			}
+			if j >= len(text) {
+				more = true
+				break loop
+			}
+			if !isNameChar(text[j]) && text[j] != '%' {
+				break loop
+			}
+			if !soft {
+				breaks = append(breaks, len(decoded))
+			}
+			i = j
+
+		default:
+			break loop
+		}
+	}
+
+	if !final && (more || i == len(text)) {
+		return Match{}, false, true
+	}
+
+	// Plain line break is joined only when the part before it is not valid on its own
+	end := len(decoded)
+	for _, index := range breaks {
+		if _, err := Parse(string(decoded[:index])); err == nil {
+			end = index
+			break
+		}
+	}
+
+	limits := []int{end}
+	for index := len(breaks) - 1; index >= 0; index-- {
+		if breaks[index] < end {
+			limits = append(limits, breaks[index])
+		}
+	}
+
+	// Trailing punctuation is left out if the name is not valid with it
+	for _, limit := range limits {
+		for n := limit; n > 0; n-- {
+			if devUrn, err := Parse(string(decoded[:n])); err == nil {
+				return Match{Start: start, End: ends[n-1], UrnDev: devUrn}, true, false
+			}
+			if !strings.ContainsRune(":_.-", rune(decoded[n-1])) {
+				break
+			}
+		}
+	}
+
+	return Match{}, false, false
+}
+
+// find scans text from pos. When final is not set scanning stops at candidate which could be extended by following text and the position of the candidate is returned, otherwise the returned position is the end of text.
+func find(text []byte, pos int, final bool) ([]Match, int) {
+	out := []Match{}
+
+	for i := pos; i < len(text); {
+		if (text[i] != 'u' && text[i] != 'U') || (i > 0 && isWordChar(text[i-1])) {
+			i++
+			continue
+		}
+
+		found, partial := prefixAt(text[i:])
+		if partial && !final {
+			return out, i
+		}
+
+		if !found {
+			i++
+			continue
+		}
+
+		match, ok, incomplete := matchAt(text, i, final)
+		if incomplete {
+			return out, i
+		}
+
+		if ok {
+			out = append(out, match)
+			i = match.End
+		} else {
+			i++
+		}
+	}
+
+	return out, len(text)
+}
+
+// FindAll locates every valid urn:dev in text. Names are recognized with any case "urn:dev:" prefix which is not preceded by a letter or digit, and URL encoded characters, e.g. "urn%3Adev%3Amac%3A0024befffe804ff1", are decoded. Trailing ":", "_", "." and "-" are left out when the name is not valid with them, so surrounding punctuation is not included. Names wrapped on several lines are joined when lines end with soft line break "=" or "\", or when the part before a plain line break is not a valid urn:dev on its own. Leading spaces and tabs of continuation lines are skipped.
+func FindAll(text []byte) []Match {
+	out, _ := find(text, 0, true)
+
+	return out
+}
+
+// Scanner finds urn:dev names from io.Reader as FindAll does for text. Match offsets are relative to the start of the input.
+type Scanner struct {
+	reader  io.Reader
+	buffer  []byte
+	offset  int
+	pos     int
+	pending []Match
+	match   Match
+	eof     bool
+	err     error
+}
+
+// NewScanner creates Scanner reading from reader.
+func NewScanner(reader io.Reader) *Scanner {
+	return &Scanner{reader: reader}
+}
+
+// Scan advances to the next match, which is then available with Match. False is returned when the input ends or reading fails, see Err.
+func (s *Scanner) Scan() bool {
+	for len(s.pending) == 0 {
+		if s.eof || s.err != nil {
+			return false
+		}
+
+		chunk := make([]byte, scannerReadSize)
+		n, err := s.reader.Read(chunk)
+		s.buffer = append(s.buffer, chunk[:n]...)
+
+		if err == io.EOF {
+			s.eof = true
+		} else if err != nil {
+			s.err = err
+			return false
+		}
+
+		matches, next := find(s.buffer, s.pos, s.eof)
+
+		// Give up waiting for candidates which do not end
+		if !s.eof && len(s.buffer)-next > MaxMatchLength {
+			matches, next = find(s.buffer[:next+MaxMatchLength], s.pos, true)
+		}
+
+		for _, match := range matches {
+			match.Start += s.offset
+			match.End += s.offset
+			s.pending = append(s.pending, match)
+		}
+
+		// Keep one byte before next position for checking what precedes urn:dev
+		if drop := next - 1; drop > 0 {
+			s.buffer = append([]byte{}, s.buffer[drop:]...)
+			s.offset += drop
+			next -= drop
+		}
+		s.pos = next
+	}
+
+	s.match, s.pending = s.pending[0], s.pending[1:]
+
+	return true
+}
+
+// Match returns the match found by the last call to Scan.
+func (s *Scanner) Match() Match {
+	return s.match
+}
+
+// Err returns the first non-EOF error encountered when reading.
+func (s *Scanner) Err() error {
+	return s.err
+}
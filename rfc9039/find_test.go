@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rfc9039
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func ExampleFindAll() {
+	text := "Device (urn:dev:mac:0024befffe804ff1) reported: see https://example.com/?id=urn%3Adev%3Aow%3A10e2073a01080063."
+	for _, match := range FindAll([]byte(text)) {
+		fmt.Println(match.Start, match.End, match.UrnDev.FullName)
+	}
+	// Output: 8 36 urn:dev:mac:0024befffe804ff1
+	// 76 109 urn:dev:ow:10e2073a01080063
+}
+
+func ExampleScanner() {
+	scanner := NewScanner(strings.NewReader("gateway urn:dev:os:32473-5002 forwarded urn:dev:ops:32473-Refrigerator-5002_compressor\n"))
+	for scanner.Scan() {
+		fmt.Println(scanner.Match().UrnDev.FullName)
+	}
+	// Output: urn:dev:os:32473-5002
+	// urn:dev:ops:32473-Refrigerator-5002_compressor
+}
+
+func TestFindAll(t *testing.T) {
+	for _, test := range []struct {
+		text  string
+		names []string
+	}{
+		{"urn:dev:mac:0024befffe804ff1", []string{"urn:dev:mac:0024befffe804ff1"}},
+		{"\"URN:DEV:ow:10e2073a01080063\", 'urn:dev:os:32473-5002'", []string{"URN:DEV:ow:10e2073a01080063", "urn:dev:os:32473-5002"}},
+		{"ends with urn:dev:mac:0024befffe804ff1.", []string{"urn:dev:mac:0024befffe804ff1"}},
+		{"(urn:dev:org:32473-foo:bar)", []string{"urn:dev:org:32473-foo:bar"}},
+		{"name=urn:dev:mac:0024befffe804ff1_eth0;next", []string{"urn:dev:mac:0024befffe804ff1_eth0"}},
+		{"urn%3adev%3Aops%3A32473-Refrigerator-5002%5Fcompressor&x=1", []string{"urn:dev:ops:32473-Refrigerator-5002_compressor"}},
+		{"wrapped urn:dev:mac:0024befffe80\n    4ff1 here", []string{"urn:dev:mac:0024befffe804ff1"}},
+		{"soft urn:dev:ops:32473-Refrigerator-50=\r\n02 break", []string{"urn:dev:ops:32473-Refrigerator-5002"}},
+		{"urn:dev:os:32473-5002\nnext line", []string{"urn:dev:os:32473-5002"}},
+		{"urn:dev:mac:0024befffe804ff1urn:dev:os:32473-5002", []string{}},
+		{"xurn:dev:os:32473-5002 urn:dev:mac:0024befffe804ff", []string{}},
+		{"urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6 urn:dev: urn%3adev", []string{}},
+	} {
+		names := []string{}
+		for _, match := range FindAll([]byte(test.text)) {
+			names = append(names, match.UrnDev.FullName)
+		}
+		assert.Equal(t, test.names, names, test.text)
+	}
+}
+
+func TestFindAllOffsets(t *testing.T) {
+	text := "a urn%3Adev%3Aos%3A32473-5002, b urn:dev:mac:0024befffe80\n4ff1\n"
+	matches := FindAll([]byte(text))
+	if len(matches) != 2 {
+		t.Fatalf("Failed to find")
+		return
+	}
+
+	assert.Equal(t, "urn%3Adev%3Aos%3A32473-5002", text[matches[0].Start:matches[0].End])
+	assert.Equal(t, "urn:dev:mac:0024befffe80\n4ff1", text[matches[1].Start:matches[1].End])
+}
+
+func TestScanner(t *testing.T) {
+	var builder strings.Builder
+	expected := []Match{}
+	for index := 0; index < 2000; index++ {
+		builder.WriteString(" log line ")
+		name := fmt.Sprintf("urn:dev:ops:32473-Refrigerator-%d", index)
+		if index%3 == 0 {
+			name = strings.ReplaceAll(name, ":", "%3A")
+		}
+		devUrn, _ := Parse(fmt.Sprintf("urn:dev:ops:32473-Refrigerator-%d", index))
+		expected = append(expected, Match{Start: builder.Len(), End: builder.Len() + len(name), UrnDev: devUrn})
+		builder.WriteString(name)
+		builder.WriteString(",\n")
+	}
+
+	for _, reader := range []io.Reader{
+		strings.NewReader(builder.String()),
+		iotest.OneByteReader(strings.NewReader(builder.String())),
+		iotest.HalfReader(strings.NewReader(builder.String())),
+	} {
+		scanner := NewScanner(reader)
+		matches := []Match{}
+		for scanner.Scan() {
+			matches = append(matches, scanner.Match())
+		}
+		assert.Nil(t, scanner.Err())
+		assert.Equal(t, expected, matches)
+	}
+}
+
+func TestScannerError(t *testing.T) {
+	failure := errors.New("failure")
+	scanner := NewScanner(io.MultiReader(strings.NewReader("urn:dev:os:32473-5002 "), iotest.ErrReader(failure)))
+	for scanner.Scan() {
+	}
+	assert.Equal(t, failure, scanner.Err())
+}
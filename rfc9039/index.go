@@ -0,0 +1,259 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rfc9039
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Entry captures urn:dev stored in Index together with its value.
+type Entry[V any] struct {
+	UrnDev UrnDev
+	Value  V
+}
+
+type indexNode[V any] struct {
+	children map[string]*indexNode[V]
+	entry    *Entry[V]
+}
+
+// Index is a prefix trie of urn:dev names built on parsed UrnDev fields: subtype, then address, organization, product and serial depending on subtype, then identifiers and finally components. The zero value is an empty index. Index is safe for concurrent use by multiple readers and writers, writes are serialized.
+type Index[V any] struct {
+	mutex sync.RWMutex
+	root  indexNode[V]
+	count int
+}
+
+// Key prefixes separating identifier and component levels of the trie. Neither can be part of an identifier.
+const (
+	indexIdentifierPrefix = ":"
+	indexComponentPrefix  = "_"
+)
+
+// indexPath returns trie path of devUrn.
+func indexPath(devUrn UrnDev) []string {
+	path := []string{devUrn.Subtype}
+
+	switch devUrn.Subtype {
+	case "mac":
+		path = append(path, devUrn.Eui64Identifier)
+	case "ow":
+		path = append(path, devUrn.OwIdentifier)
+	case "org":
+		path = append(path, devUrn.Organization)
+	case "os":
+		path = append(path, devUrn.Organization, devUrn.Serial)
+	case "ops":
+		path = append(path, devUrn.Organization, devUrn.Product, devUrn.Serial)
+	}
+
+	for _, identifier := range devUrn.Identifier {
+		path = append(path, indexIdentifierPrefix+identifier)
+	}
+
+	for _, component := range devUrn.Component {
+		path = append(path, indexComponentPrefix+component)
+	}
+
+	return path
+}
+
+// node returns trie node at path or nil if there is none.
+func (x *Index[V]) node(path ...string) *indexNode[V] {
+	node := &x.root
+
+	for _, key := range path {
+		if node = node.children[key]; node == nil {
+			return nil
+		}
+	}
+
+	return node
+}
+
+// Put stores value for devUrn replacing previous value of the same name. An error is returned if FullName is not a valid urn:dev.
+func (x *Index[V]) Put(devUrn UrnDev, value V) error {
+	parsed, err := Parse(devUrn.FullName)
+	if err != nil {
+		return err
+	}
+
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	node := &x.root
+	for _, key := range indexPath(parsed) {
+		if node.children == nil {
+			node.children = map[string]*indexNode[V]{}
+		}
+
+		child, ok := node.children[key]
+		if !ok {
+			child = &indexNode[V]{}
+			node.children[key] = child
+		}
+		node = child
+	}
+
+	if node.entry == nil {
+		x.count++
+	}
+	node.entry = &Entry[V]{UrnDev: parsed, Value: value}
+
+	return nil
+}
+
+// Delete removes devUrn from the index. Components of devUrn are kept. False is returned if devUrn was not found.
+func (x *Index[V]) Delete(devUrn UrnDev) bool {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	path := indexPath(devUrn)
+	nodes := []*indexNode[V]{&x.root}
+	for _, key := range path {
+		child := nodes[len(nodes)-1].children[key]
+		if child == nil {
+			return false
+		}
+		nodes = append(nodes, child)
+	}
+
+	if nodes[len(nodes)-1].entry == nil {
+		return false
+	}
+
+	nodes[len(nodes)-1].entry = nil
+	x.count--
+
+	// Prune nodes left without entries and children
+	for i := len(path) - 1; i >= 0; i-- {
+		node := nodes[i+1]
+		if node.entry != nil || len(node.children) > 0 {
+			break
+		}
+		delete(nodes[i].children, path[i])
+	}
+
+	return true
+}
+
+// Len returns the number of names in the index.
+func (x *Index[V]) Len() int {
+	x.mutex.RLock()
+	defer x.mutex.RUnlock()
+
+	return x.count
+}
+
+// Get returns entry stored for devUrn. False is returned if devUrn was not found.
+func (x *Index[V]) Get(devUrn UrnDev) (Entry[V], bool) {
+	x.mutex.RLock()
+	defer x.mutex.RUnlock()
+
+	node := x.node(indexPath(devUrn)...)
+	if node == nil || node.entry == nil {
+		return Entry[V]{}, false
+	}
+
+	return *node.entry, true
+}
+
+// LongestMatch returns the entry of the nearest registered name covering devUrn: devUrn itself, or the closest parent component, device or device with fewer identifiers. E.g. for "urn:dev:ops:32473-Refrigerator-5002_compressor_motor" entry of "urn:dev:ops:32473-Refrigerator-5002_compressor" is returned if it is registered, otherwise entry of "urn:dev:ops:32473-Refrigerator-5002". False is returned if there is no such entry.
+func (x *Index[V]) LongestMatch(devUrn UrnDev) (Entry[V], bool) {
+	x.mutex.RLock()
+	defer x.mutex.RUnlock()
+
+	var out *Entry[V]
+
+	node := &x.root
+	for _, key := range indexPath(devUrn) {
+		if node = node.children[key]; node == nil {
+			break
+		}
+		if node.entry != nil {
+			out = node.entry
+		}
+	}
+
+	if out == nil {
+		return Entry[V]{}, false
+	}
+
+	return *out, true
+}
+
+// collect appends entries of node and its descendants. Component levels are followed only when components is set.
+func (node *indexNode[V]) collect(out []Entry[V], components bool) []Entry[V] {
+	if node.entry != nil {
+		out = append(out, *node.entry)
+	}
+
+	for key, child := range node.children {
+		if components || !strings.HasPrefix(key, indexComponentPrefix) {
+			out = child.collect(out, components)
+		}
+	}
+
+	return out
+}
+
+func sortEntries[V any](entries []Entry[V]) []Entry[V] {
+	slices.SortFunc(entries, func(a Entry[V], b Entry[V]) int {
+		return Compare(a.UrnDev, b.UrnDev)
+	})
+
+	return entries
+}
+
+// Components returns entries of all components below devUrn at any depth, ordered with Compare. Entry of devUrn itself and devices with additional identifiers are not included.
+func (x *Index[V]) Components(devUrn UrnDev) []Entry[V] {
+	x.mutex.RLock()
+	defer x.mutex.RUnlock()
+
+	out := []Entry[V]{}
+
+	node := x.node(indexPath(devUrn)...)
+	if node == nil {
+		return out
+	}
+
+	for key, child := range node.children {
+		if strings.HasPrefix(key, indexComponentPrefix) {
+			out = child.collect(out, true)
+		}
+	}
+
+	return sortEntries(out)
+}
+
+// DevicesByOrganization returns entries of all "org", "os" and "ops" devices of organization, ordered with Compare. Components are not included.
+func (x *Index[V]) DevicesByOrganization(organization string) []Entry[V] {
+	x.mutex.RLock()
+	defer x.mutex.RUnlock()
+
+	out := []Entry[V]{}
+
+	for _, subtype := range []string{"org", "os", "ops"} {
+		if node := x.node(subtype, organization); node != nil {
+			out = node.collect(out, false)
+		}
+	}
+
+	return sortEntries(out)
+}
+
+// DevicesByProduct returns entries of all "ops" devices of organization and product, ordered with Compare. Components are not included.
+func (x *Index[V]) DevicesByProduct(organization string, product string) []Entry[V] {
+	x.mutex.RLock()
+	defer x.mutex.RUnlock()
+
+	out := []Entry[V]{}
+
+	if node := x.node("ops", organization, product); node != nil {
+		out = node.collect(out, false)
+	}
+
+	return sortEntries(out)
+}
@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rfc9039
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func ExampleIndex_LongestMatch() {
+	index := &Index[string]{}
+	devUrn, _ := Parse("urn:dev:ops:32473-Refrigerator-5002")
+	_ = index.Put(devUrn, "kitchen")
+
+	component, _ := Parse("urn:dev:ops:32473-Refrigerator-5002_compressor_motor")
+	entry, _ := index.LongestMatch(component)
+	fmt.Println(entry.UrnDev.FullName, entry.Value)
+	// Output: urn:dev:ops:32473-Refrigerator-5002 kitchen
+}
+
+func mustParse(t *testing.T, name string) UrnDev {
+	devUrn, err := Parse(name)
+	if err != nil {
+		t.Fatalf("Failed to parse %s", name)
+	}
+
+	return devUrn
+}
+
+func entryNames[V any](entries []Entry[V]) []string {
+	out := []string{}
+	for _, entry := range entries {
+		out = append(out, entry.UrnDev.FullName)
+	}
+
+	return out
+}
+
+func newTestIndex(t *testing.T) *Index[int] {
+	index := &Index[int]{}
+
+	for i, name := range []string{
+		"urn:dev:ops:32473-Refrigerator-5002",
+		"urn:dev:ops:32473-Refrigerator-5002_compressor",
+		"urn:dev:ops:32473-Refrigerator-5002_compressor_motor",
+		"urn:dev:ops:32473-Refrigerator-5002_fan",
+		"urn:dev:ops:32473-Refrigerator-5002:foo",
+		"urn:dev:ops:32473-Refrigerator-5002:foo_door",
+		"urn:dev:ops:32473-Refrigerator-10",
+		"urn:dev:ops:32473-Freezer-1",
+		"urn:dev:ops:3247-Refrigerator-5002",
+		"urn:dev:os:32473-5002",
+		"urn:dev:org:32473-foo_bar",
+		"urn:dev:mac:0024befffe804ff1",
+		"urn:dev:mac:0024befffe804ff1_eth0",
+	} {
+		err := index.Put(mustParse(t, name), i)
+		if err != nil {
+			t.Fatalf("Failed to put %s", name)
+		}
+	}
+
+	return index
+}
+
+func TestIndexGet(t *testing.T) {
+	index := newTestIndex(t)
+	assert.Equal(t, 13, index.Len())
+
+	entry, ok := index.Get(mustParse(t, "urn:dev:ops:32473-Refrigerator-5002_compressor"))
+	assert.True(t, ok)
+	assert.Equal(t, 1, entry.Value)
+	assert.Equal(t, []string{"compressor"}, entry.UrnDev.Component)
+
+	entry, ok = index.Get(mustParse(t, "URN:DEV:mac:0024befffe804ff1"))
+	assert.True(t, ok)
+	assert.Equal(t, 11, entry.Value)
+	assert.Equal(t, "urn:dev:mac:0024befffe804ff1", entry.UrnDev.FullName)
+
+	for _, name := range []string{
+		"urn:dev:ops:32473-Refrigerator-5003",
+		"urn:dev:ops:32473-Refrigerator-5002_door",
+		"urn:dev:org:32473-foo",
+		"urn:dev:ow:0024befffe804ff1",
+	} {
+		_, ok = index.Get(mustParse(t, name))
+		assert.False(t, ok, name)
+	}
+
+	err := index.Put(mustParse(t, "urn:dev:ops:32473-Refrigerator-5002"), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 13, index.Len())
+
+	entry, _ = index.Get(mustParse(t, "urn:dev:ops:32473-Refrigerator-5002"))
+	assert.Equal(t, 100, entry.Value)
+
+	err = index.Put(UrnDev{FullName: "urn:dev:mac:0024befffe804ff"}, 0)
+	assert.Error(t, err)
+}
+
+func TestIndexComponents(t *testing.T) {
+	index := newTestIndex(t)
+
+	assert.Equal(t, []string{
+		"urn:dev:ops:32473-Refrigerator-5002_compressor",
+		"urn:dev:ops:32473-Refrigerator-5002_compressor_motor",
+		"urn:dev:ops:32473-Refrigerator-5002_fan",
+	}, entryNames(index.Components(mustParse(t, "urn:dev:ops:32473-Refrigerator-5002"))))
+
+	assert.Equal(t, []string{
+		"urn:dev:ops:32473-Refrigerator-5002_compressor_motor",
+	}, entryNames(index.Components(mustParse(t, "urn:dev:ops:32473-Refrigerator-5002_compressor"))))
+
+	// Device does not need to be registered itself
+	assert.Equal(t, []string{
+		"urn:dev:org:32473-foo_bar",
+	}, entryNames(index.Components(mustParse(t, "urn:dev:org:32473-foo"))))
+
+	assert.Equal(t, []string{}, entryNames(index.Components(mustParse(t, "urn:dev:ow:0024befffe804ff1"))))
+}
+
+func TestIndexDevices(t *testing.T) {
+	index := newTestIndex(t)
+
+	assert.Equal(t, []string{
+		"urn:dev:ops:32473-Freezer-1",
+		"urn:dev:ops:32473-Refrigerator-10",
+		"urn:dev:ops:32473-Refrigerator-5002",
+		"urn:dev:ops:32473-Refrigerator-5002:foo",
+		"urn:dev:os:32473-5002",
+	}, entryNames(index.DevicesByOrganization("32473")))
+
+	assert.Equal(t, []string{
+		"urn:dev:ops:32473-Refrigerator-10",
+		"urn:dev:ops:32473-Refrigerator-5002",
+		"urn:dev:ops:32473-Refrigerator-5002:foo",
+	}, entryNames(index.DevicesByProduct("32473", "Refrigerator")))
+
+	assert.Equal(t, []string{}, entryNames(index.DevicesByOrganization("1")))
+	assert.Equal(t, []string{}, entryNames(index.DevicesByProduct("32473", "Oven")))
+}
+
+func TestIndexLongestMatch(t *testing.T) {
+	index := newTestIndex(t)
+
+	for _, test := range []struct {
+		name   string
+		parent string
+	}{
+		{"urn:dev:ops:32473-Refrigerator-5002_compressor_motor_coil", "urn:dev:ops:32473-Refrigerator-5002_compressor_motor"},
+		{"urn:dev:ops:32473-Refrigerator-5002_fan", "urn:dev:ops:32473-Refrigerator-5002_fan"},
+		{"urn:dev:ops:32473-Refrigerator-5002_door", "urn:dev:ops:32473-Refrigerator-5002"},
+		{"urn:dev:ops:32473-Refrigerator-5002:bar_door", "urn:dev:ops:32473-Refrigerator-5002"},
+		{"urn:dev:ops:32473-Refrigerator-5002:foo_door_handle", "urn:dev:ops:32473-Refrigerator-5002:foo_door"},
+		{"urn:dev:mac:0024befffe804ff1_eth1", "urn:dev:mac:0024befffe804ff1"},
+	} {
+		entry, ok := index.LongestMatch(mustParse(t, test.name))
+		assert.True(t, ok, test.name)
+		assert.Equal(t, test.parent, entry.UrnDev.FullName, test.name)
+	}
+
+	for _, name := range []string{
+		"urn:dev:ops:32473-Refrigerator-5003_compressor",
+		"urn:dev:org:32473-foo_baz",
+		"urn:dev:mac:0024befffe804ff2_eth0",
+	} {
+		_, ok := index.LongestMatch(mustParse(t, name))
+		assert.False(t, ok, name)
+	}
+}
+
+func TestIndexDelete(t *testing.T) {
+	index := newTestIndex(t)
+
+	assert.True(t, index.Delete(mustParse(t, "urn:dev:ops:32473-Refrigerator-5002")))
+	assert.False(t, index.Delete(mustParse(t, "urn:dev:ops:32473-Refrigerator-5002")))
+	assert.False(t, index.Delete(mustParse(t, "urn:dev:ops:32473-Refrigerator-5003")))
+	assert.Equal(t, 12, index.Len())
+
+	_, ok := index.Get(mustParse(t, "urn:dev:ops:32473-Refrigerator-5002"))
+	assert.False(t, ok)
+	assert.Equal(t, 3, len(index.Components(mustParse(t, "urn:dev:ops:32473-Refrigerator-5002"))))
+
+	assert.True(t, index.Delete(mustParse(t, "urn:dev:ops:32473-Freezer-1")))
+	assert.Nil(t, index.node("ops", "32473", "Freezer"))
+
+	assert.True(t, index.Delete(mustParse(t, "urn:dev:os:32473-5002")))
+	assert.Nil(t, index.node("os"))
+	assert.NotNil(t, index.node("ops", "32473", "Refrigerator"))
+}
+
+func TestIndexConcurrent(t *testing.T) {
+	index := &Index[int]{}
+	devUrn := mustParse(t, "urn:dev:ops:32473-Refrigerator-5002")
+	component := mustParse(t, "urn:dev:ops:32473-Refrigerator-5002_compressor")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				index.Get(devUrn)
+				index.LongestMatch(component)
+				index.DevicesByOrganization("32473")
+			}
+		}()
+	}
+
+	for j := 0; j < 1000; j++ {
+		_ = index.Put(devUrn, j)
+		_ = index.Put(component, j)
+		index.Delete(component)
+	}
+	wg.Wait()
+
+	entry, ok := index.Get(devUrn)
+	assert.True(t, ok)
+	assert.Equal(t, 999, entry.Value)
+}
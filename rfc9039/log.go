@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rfc9039
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+)
+
+// RedactionPolicy selects how urn:dev is shown in logs.
+type RedactionPolicy int
+
+const (
+	// RedactFull shows urn:dev as is.
+	RedactFull RedactionPolicy = iota
+	// RedactHash replaces identifying part with a keyed hash of the device name. Without a key identifying part is masked as with RedactMaskSerial, since unkeyed hashes of addresses and serial numbers can be reversed by brute force.
+	RedactHash
+	// RedactMaskSerial replaces identifying part with mask characters.
+	RedactMaskSerial
+	// RedactMaskEUITail keeps the first 6 hex digits of EUI-64 and 1-Wire addresses and masks the rest. Other subtypes are masked as with RedactMaskSerial.
+	RedactMaskEUITail
+)
+
+// Mask replaces masked serial numbers and identifiers in redacted urn:dev names. Mask characters are not valid in urn:dev.
+const Mask = "****"
+
+// hashDigits is the number of hex digits shown by RedactHash.
+const hashDigits = 16
+
+func (p RedactionPolicy) String() string {
+	switch p {
+	case RedactFull:
+		return "full"
+	case RedactHash:
+		return "hash"
+	case RedactMaskSerial:
+		return "mask-serial"
+	case RedactMaskEUITail:
+		return "mask-eui-tail"
+	default:
+		return "unknown"
+	}
+}
+
+// Redact returns urn:dev with its identifying part redacted according to policy while subtype, organization, product and components are kept. Identifying part is EUI-64 and 1-Wire address for "mac" and "ow", serial for "os" and "ops" and identifiers for "org" and other subtypes, and any identifiers following them are redacted as well. E.g. with RedactMaskSerial "urn:dev:ops:32473-Refrigerator-5002_compressor" is shown as "urn:dev:ops:32473-Refrigerator-****_compressor" and with RedactMaskEUITail "urn:dev:mac:0024befffe804ff1" is shown as "urn:dev:mac:0024be**********". RedactHash shows "#" followed by 16 hex digits of HMAC-SHA256 with key of the device name without components and with lower case "urn:dev:" prefix, so that names of the same device share the hash. Without key RedactHash is handled as RedactMaskSerial and no hash is shown. If FullName is not a valid urn:dev Mask is returned.
+func (u UrnDev) Redact(policy RedactionPolicy, key []byte) string {
+	devUrn, err := Parse(u.FullName)
+	if err != nil {
+		return Mask
+	}
+
+	if policy == RedactFull {
+		return devUrn.FullName
+	}
+
+	if policy == RedactHash && len(key) == 0 {
+		policy = RedactMaskSerial
+	}
+
+	secret := Mask
+	if policy == RedactHash {
+		device := UrnDevPrefix + devUrn.FullName[len(UrnDevPrefix):]
+		if index := strings.IndexByte(device, '_'); index >= 0 {
+			device = device[:index]
+		}
+
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(device))
+
+		secret = "#" + hex.EncodeToString(h.Sum(nil))[:hashDigits]
+	}
+
+	var body string
+
+	switch devUrn.Subtype {
+	case "mac", "ow":
+		address := devUrn.Eui64Identifier + devUrn.OwIdentifier
+		switch policy {
+		case RedactMaskEUITail:
+			body = address[:6] + strings.Repeat("*", len(address)-6)
+		case RedactMaskSerial:
+			body = strings.Repeat("*", len(address))
+		default:
+			body = secret
+		}
+	case "org", "os":
+		body = devUrn.Organization + "-" + secret
+	case "ops":
+		body = devUrn.Organization + "-" + devUrn.Product + "-" + secret
+	default:
+		body = secret
+	}
+
+	name := UrnDevPrefix + devUrn.Subtype + ":" + body
+	for _, component := range devUrn.Component {
+		name += "_" + component
+	}
+
+	return name
+}
+
+// LogValue implements slog.LogValuer. urn:dev is logged as a group of "subtype", "organization" and "product", when present, and "id" holding the name redacted with RedactMaskEUITail. Handlers can select another policy, see package urnslog.
+func (u UrnDev) LogValue() slog.Value {
+	return u.RedactedLogValue(RedactMaskEUITail, nil)
+}
+
+// RedactedLogValue returns slog group value as LogValue with "id" redacted according to policy, see Redact.
+func (u UrnDev) RedactedLogValue(policy RedactionPolicy, key []byte) slog.Value {
+	attrs := []slog.Attr{slog.String("subtype", u.Subtype)}
+
+	if u.Organization != "" {
+		attrs = append(attrs, slog.String("organization", u.Organization))
+	}
+
+	if u.Product != "" {
+		attrs = append(attrs, slog.String("product", u.Product))
+	}
+
+	attrs = append(attrs, slog.String("id", u.Redact(policy, key)))
+
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rfc9039
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+func ExampleUrnDev_Redact() {
+	devUrn, _ := Parse("urn:dev:mac:0024befffe804ff1_eth0")
+	fmt.Println(devUrn.Redact(RedactMaskEUITail, nil))
+	fmt.Println(devUrn.Redact(RedactMaskSerial, nil))
+	// Output: urn:dev:mac:0024be**********_eth0
+	// urn:dev:mac:****************_eth0
+}
+
+func TestRedact(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		masked string
+	}{
+		{"urn:dev:ow:10e2073a01080063", "urn:dev:ow:10e207**********"},
+		{"urn:dev:mac:0024befffe804ff1:a:b", "urn:dev:mac:0024be**********"},
+		{"urn:dev:org:32473-foo:bar_c", "urn:dev:org:32473-****_c"},
+		{"urn:dev:os:32473-12-34", "urn:dev:os:32473-****"},
+		{"URN:DEV:ops:32473-Refrigerator-5002_compressor_motor", "urn:dev:ops:32473-Refrigerator-****_compressor_motor"},
+		{"urn:dev:example:foo:bar", "urn:dev:example:****"},
+	} {
+		devUrn, err := Parse(test.name)
+		if err != nil {
+			t.Fatalf("Failed to parse %s", test.name)
+			return
+		}
+		assert.Equal(t, test.masked, devUrn.Redact(RedactMaskEUITail, nil), test.name)
+		assert.Equal(t, test.name, devUrn.Redact(RedactFull, nil), test.name)
+	}
+
+	assert.Equal(t, Mask, UrnDev{}.Redact(RedactFull, nil))
+}
+
+func TestRedactHash(t *testing.T) {
+	device, _ := Parse("urn:dev:ops:32473-Refrigerator-5002")
+	component, _ := Parse("URN:DEV:ops:32473-Refrigerator-5002_compressor")
+	other, _ := Parse("urn:dev:ops:32473-Refrigerator-5003")
+
+	key := []byte("secret")
+
+	hash := device.Redact(RedactHash, key)
+	assert.Regexp(t, "^urn:dev:ops:32473-Refrigerator-#[0-9a-f]{16}$", hash)
+	assert.Equal(t, hash+"_compressor", component.Redact(RedactHash, key))
+	assert.NotEqual(t, hash, other.Redact(RedactHash, key))
+	assert.NotEqual(t, hash, device.Redact(RedactHash, []byte("other secret")))
+	assert.Equal(t, hash, device.Redact(RedactHash, key))
+}
+
+func TestRedactHashWithoutKey(t *testing.T) {
+	device, _ := Parse("urn:dev:ops:32473-Refrigerator-5002_compressor")
+	digest := sha256.Sum256([]byte("urn:dev:ops:32473-Refrigerator-5002"))
+
+	// Unkeyed hash could be reversed by brute force, so the serial is masked instead
+	for _, key := range [][]byte{nil, {}} {
+		redacted := device.Redact(RedactHash, key)
+		assert.Equal(t, "urn:dev:ops:32473-Refrigerator-****_compressor", redacted)
+		assert.NotContains(t, redacted, "#")
+		assert.NotContains(t, redacted, hex.EncodeToString(digest[:])[:hashDigits])
+	}
+}
+
+func TestLogValue(t *testing.T) {
+	devUrn, _ := Parse("urn:dev:ops:32473-Refrigerator-5002")
+
+	value := devUrn.LogValue()
+	assert.Equal(t, slog.KindGroup, value.Kind())
+	assert.Equal(t, []slog.Attr{
+		slog.String("subtype", "ops"),
+		slog.String("organization", "32473"),
+		slog.String("product", "Refrigerator"),
+		slog.String("id", "urn:dev:ops:32473-Refrigerator-****"),
+	}, value.Group())
+
+	assert.Equal(t, "mask-serial", RedactMaskSerial.String())
+}
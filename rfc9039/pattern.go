@@ -0,0 +1,348 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rfc9039
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const PatternFieldRegEx = "^[A-Za-z0-9\\.\\-\\*\\?]+$"
+const PatternAddressRegEx = "^[0-9a-f\\*\\?]+$"
+const PatternOrganizationRegEx = "^[0-9\\*\\?]+$"
+const PatternOrganizationRangeRegEx = "^\\[([1-9][0-9]*)-([1-9][0-9]*)\\]$"
+
+// glob matches single field value. Patterns without wildcards are compared directly.
+type glob struct {
+	pattern string
+	literal bool
+}
+
+func newGlob(pattern string) glob {
+	return glob{pattern: pattern, literal: !strings.ContainsAny(pattern, "*?")}
+}
+
+func (g glob) match(value string) bool {
+	if g.literal {
+		return g.pattern == value
+	}
+
+	match, _ := path.Match(g.pattern, value)
+
+	return match
+}
+
+// Pattern captures ParsePattern output. Pattern is matched against parsed UrnDev fields.
+type Pattern struct {
+	source       string
+	subtype      string
+	address      glob
+	organization glob
+	minPEN       uint64
+	maxPEN       uint64
+	product      glob
+	serial       glob
+	identifiers  []glob
+	components   []glob
+}
+
+func isValidPatternField(name string) bool {
+	match, _ := regexp.MatchString(PatternFieldRegEx, name)
+
+	return match
+}
+
+func isValidPatternAddress(name string) bool {
+	match, _ := regexp.MatchString(PatternAddressRegEx, name)
+
+	return match
+}
+
+func isValidPatternOrganization(name string) bool {
+	match, _ := regexp.MatchString(PatternOrganizationRegEx, name)
+
+	return match
+}
+
+func parsePatternFields(fields []string) ([]glob, error) {
+	out := []glob{}
+
+	for _, field := range fields {
+		if !isValidPatternField(field) {
+			return nil, errors.New("invalid pattern (" + field + ")")
+		}
+		out = append(out, newGlob(field))
+	}
+
+	return out, nil
+}
+
+// parsePatternOrganization parses organization glob or range from the start of body and returns the rest of body after the dash.
+func (p *Pattern) parsePatternOrganization(body string) (string, error) {
+	var organization, rest string
+
+	if strings.HasPrefix(body, "[") {
+		index := strings.IndexByte(body, ']')
+		if index < 0 {
+			return "", errors.New("invalid pattern (organization)")
+		}
+		organization, rest = body[:index+1], body[index+1:]
+		if rest != "" && rest[0] != '-' {
+			return "", errors.New("invalid pattern (organization)")
+		}
+		rest = strings.TrimPrefix(rest, "-")
+
+		match := regexp.MustCompile(PatternOrganizationRangeRegEx).FindStringSubmatch(organization)
+		if match == nil {
+			return "", errors.New("invalid pattern (organization)")
+		}
+
+		var err1, err2 error
+		p.minPEN, err1 = strconv.ParseUint(match[1], 10, 64)
+		p.maxPEN, err2 = strconv.ParseUint(match[2], 10, 64)
+		if err1 != nil || err2 != nil || p.minPEN > p.maxPEN {
+			return "", errors.New("invalid pattern (organization)")
+		}
+	} else {
+		parts := strings.SplitN(body, "-", 2)
+		organization = parts[0]
+		if len(parts) == 2 {
+			rest = parts[1]
+		}
+
+		if !isValidPatternOrganization(organization) {
+			return "", errors.New("invalid pattern (organization)")
+		}
+		p.organization = newGlob(organization)
+	}
+
+	if rest == "" {
+		return "", errors.New("invalid pattern (organization)")
+	}
+
+	return rest, nil
+}
+
+// ParsePattern parses urn:dev pattern. Pattern follows urn:dev syntax with literal subtype and fields which may contain glob wildcards "*", matching any run of characters, and "?", matching any single character. Wildcards do not cross field boundaries, e.g. in "urn:dev:ops:32473-Refrigerator-*" the "*" matches only the serial. Organization may also be given as inclusive numeric range, e.g. "urn:dev:os:[32473-32480]-*". Identifiers and components are matched element by element and the number of elements needs to be equal, except that "*" as the last element matches one or more remaining elements, e.g. "urn:dev:mac:0024be*_*" matches all components of devices with EUI-64 starting with "0024be" but not the devices themselves. If incorrectly formed pattern is given as input an error is returned.
+func ParsePattern(pattern string) (Pattern, error) {
+	out := Pattern{source: pattern}
+
+	sections := strings.Split(pattern, ":")
+
+	if len(sections) < 4 || len(sections) >= UrnDevMaxSectionCount {
+		return Pattern{}, errors.New("invalid pattern")
+	}
+
+	if strings.ToLower(sections[0]) != "urn" || strings.ToLower(sections[1]) != "dev" {
+		return Pattern{}, errors.New("invalid pattern (missing urn:dev)")
+	}
+
+	out.subtype = sections[2]
+	if !isValidSubType(out.subtype) {
+		return Pattern{}, errors.New("invalid pattern (subtype)")
+	}
+
+	componentPart := strings.Split(sections[len(sections)-1], "_")
+	sections[len(sections)-1] = componentPart[0]
+
+	var err error
+	if out.components, err = parsePatternFields(componentPart[1:]); err != nil {
+		return Pattern{}, err
+	}
+
+	body, identifiers := sections[3], sections[4:]
+
+	switch out.subtype {
+	case "mac", "ow":
+		if !isValidPatternAddress(body) {
+			return Pattern{}, errors.New("invalid pattern (address)")
+		}
+		out.address = newGlob(body)
+
+	case "org":
+		if body, err = out.parsePatternOrganization(body); err != nil {
+			return Pattern{}, err
+		}
+		identifiers = append([]string{body}, identifiers...)
+
+	case "os":
+		if body, err = out.parsePatternOrganization(body); err != nil {
+			return Pattern{}, err
+		}
+		if !isValidPatternField(body) {
+			return Pattern{}, errors.New("invalid pattern (serial)")
+		}
+		out.serial = newGlob(body)
+
+	case "ops":
+		if body, err = out.parsePatternOrganization(body); err != nil {
+			return Pattern{}, err
+		}
+		ops := strings.Split(body, "-")
+		if len(ops) != 2 || !isValidPatternField(ops[0]) || !isValidPatternField(ops[1]) {
+			return Pattern{}, errors.New("invalid pattern (ops)")
+		}
+		out.product, out.serial = newGlob(ops[0]), newGlob(ops[1])
+
+	default:
+		identifiers = append([]string{body}, identifiers...)
+	}
+
+	if out.identifiers, err = parsePatternFields(identifiers); err != nil {
+		return Pattern{}, err
+	}
+
+	return out, nil
+}
+
+// String returns the pattern as it was given to ParsePattern.
+func (p Pattern) String() string {
+	return p.source
+}
+
+func matchList(globs []glob, values []string) bool {
+	count := len(globs)
+
+	if count > 0 && globs[count-1].pattern == "*" {
+		// Trailing "*" matches one or more remaining elements
+		if len(values) < count {
+			return false
+		}
+		count--
+	} else if len(values) != count {
+		return false
+	}
+
+	for i := 0; i < count; i++ {
+		if !globs[i].match(values[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Match checks whether parsed urn:dev matches the pattern.
+func (p Pattern) Match(devUrn UrnDev) bool {
+	if p.subtype != devUrn.Subtype {
+		return false
+	}
+
+	if p.maxPEN > 0 {
+		pen, err := strconv.ParseUint(devUrn.Organization, 10, 64)
+		if err != nil || pen < p.minPEN || pen > p.maxPEN {
+			return false
+		}
+	} else if !p.organization.match(devUrn.Organization) {
+		return false
+	}
+
+	return p.address.match(devUrn.Eui64Identifier+devUrn.OwIdentifier) &&
+		p.product.match(devUrn.Product) &&
+		p.serial.match(devUrn.Serial) &&
+		matchList(p.identifiers, devUrn.Identifier) &&
+		matchList(p.components, devUrn.Component)
+}
+
+// key returns the literal value patterns are indexed by in Matcher: address for "mac" and "ow", organization for "org", "os" and "ops" and first identifier for other subtypes. False is returned when the value contains wildcards or is a range.
+func (p Pattern) key() (string, bool) {
+	var value glob
+
+	switch p.subtype {
+	case "mac", "ow":
+		value = p.address
+	case "org", "os", "ops":
+		if p.maxPEN > 0 {
+			return "", false
+		}
+		value = p.organization
+	default:
+		value = p.identifiers[0]
+	}
+
+	return value.pattern, value.literal
+}
+
+// keyOf returns the value of devUrn that corresponds to Pattern key.
+func keyOf(devUrn UrnDev) string {
+	switch devUrn.Subtype {
+	case "mac":
+		return devUrn.Eui64Identifier
+	case "ow":
+		return devUrn.OwIdentifier
+	case "org", "os", "ops":
+		return devUrn.Organization
+	default:
+		if len(devUrn.Identifier) == 0 {
+			return ""
+		}
+		return devUrn.Identifier[0]
+	}
+}
+
+// matcherBucket holds indexes of patterns of single subtype.
+type matcherBucket struct {
+	keyed map[string][]int
+	other []int
+}
+
+// Matcher matches urn:dev against a set of patterns. Patterns are indexed by subtype and by literal address, organization or first identifier so that only patterns which can match are evaluated.
+type Matcher struct {
+	patterns []Pattern
+	buckets  map[string]*matcherBucket
+}
+
+// NewMatcher compiles patterns into a Matcher. Matcher is not modified after creation and can be used concurrently.
+func NewMatcher(patterns ...Pattern) *Matcher {
+	out := &Matcher{patterns: patterns, buckets: map[string]*matcherBucket{}}
+
+	for index, pattern := range patterns {
+		bucket, ok := out.buckets[pattern.subtype]
+		if !ok {
+			bucket = &matcherBucket{keyed: map[string][]int{}}
+			out.buckets[pattern.subtype] = bucket
+		}
+
+		if key, ok := pattern.key(); ok {
+			bucket.keyed[key] = append(bucket.keyed[key], index)
+		} else {
+			bucket.other = append(bucket.other, index)
+		}
+	}
+
+	return out
+}
+
+// First returns index of the first pattern, in the order given to NewMatcher, which matches devUrn. If no pattern matches -1 is returned.
+func (m *Matcher) First(devUrn UrnDev) int {
+	bucket, ok := m.buckets[devUrn.Subtype]
+	if !ok {
+		return -1
+	}
+
+	keyed, other := bucket.keyed[keyOf(devUrn)], bucket.other
+
+	// Both lists are in ascending order, walk them in pattern order
+	for len(keyed) > 0 || len(other) > 0 {
+		var index int
+		if len(other) == 0 || (len(keyed) > 0 && keyed[0] < other[0]) {
+			index, keyed = keyed[0], keyed[1:]
+		} else {
+			index, other = other[0], other[1:]
+		}
+
+		if m.patterns[index].Match(devUrn) {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// Match checks whether devUrn matches any of the patterns.
+func (m *Matcher) Match(devUrn UrnDev) bool {
+	return m.First(devUrn) >= 0
+}
@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package rfc9039
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func ExampleParsePattern() {
+	pattern, _ := ParsePattern("urn:dev:ops:32473-Refrigerator-*")
+	devUrn, _ := Parse("urn:dev:ops:32473-Refrigerator-5002")
+	fmt.Println(pattern.Match(devUrn))
+	devUrn, _ = Parse("urn:dev:ops:32473-Freezer-5002")
+	fmt.Println(pattern.Match(devUrn))
+	// Output: true
+	// false
+}
+
+func assertPattern(t *testing.T, pattern string, matching []string, notMatching []string) {
+	value, err := ParsePattern(pattern)
+	if err != nil {
+		t.Fatalf("Failed to parse %s: %v", pattern, err)
+		return
+	}
+	assert.Equal(t, pattern, value.String())
+
+	for _, name := range matching {
+		devUrn, err := Parse(name)
+		if err != nil {
+			t.Fatalf("Failed to parse %s", name)
+			return
+		}
+		assert.True(t, value.Match(devUrn), pattern+" "+name)
+	}
+
+	for _, name := range notMatching {
+		devUrn, err := Parse(name)
+		if err != nil {
+			t.Fatalf("Failed to parse %s", name)
+			return
+		}
+		assert.False(t, value.Match(devUrn), pattern+" "+name)
+	}
+}
+
+func TestPatternMac(t *testing.T) {
+	assertPattern(t, "urn:dev:mac:0024be*_*",
+		[]string{"urn:dev:mac:0024befffe804ff1_eth0", "urn:dev:mac:0024be0000000000_eth0_rx"},
+		[]string{"urn:dev:mac:0024befffe804ff1", "urn:dev:mac:0024bffffe804ff1_eth0", "urn:dev:ow:0024befffe804ff1_eth0"})
+
+	assertPattern(t, "urn:dev:mac:0024befffe804ff?",
+		[]string{"urn:dev:mac:0024befffe804ff1", "urn:dev:mac:0024befffe804ffa"},
+		[]string{"urn:dev:mac:0024befffe804f01", "urn:dev:mac:0024befffe804ff1_eth0"})
+}
+
+func TestPatternOps(t *testing.T) {
+	assertPattern(t, "urn:dev:ops:32473-Refrigerator-*",
+		[]string{"urn:dev:ops:32473-Refrigerator-5002", "urn:dev:ops:32473-Refrigerator-1"},
+		[]string{"urn:dev:ops:32473-Freezer-5002", "urn:dev:ops:32474-Refrigerator-5002", "urn:dev:ops:32473-Refrigerator-5002_compressor", "urn:dev:ops:32473-Refrigerator-5002:foo"})
+
+	assertPattern(t, "urn:dev:ops:[32473-32480]-*-50??:*_compressor",
+		[]string{"urn:dev:ops:32473-Refrigerator-5002:foo_compressor", "urn:dev:ops:32480-Freezer-5099:a:b_compressor"},
+		[]string{"urn:dev:ops:32472-Refrigerator-5002:foo_compressor", "urn:dev:ops:32481-Refrigerator-5002:foo_compressor", "urn:dev:ops:32473-Refrigerator-5002_compressor", "urn:dev:ops:32473-Refrigerator-500:foo_compressor"})
+}
+
+func TestPatternOrgOs(t *testing.T) {
+	assertPattern(t, "urn:dev:org:*-foo:*",
+		[]string{"urn:dev:org:32473-foo:bar", "urn:dev:org:1-foo:bar:zoo"},
+		[]string{"urn:dev:org:32473-foo", "urn:dev:org:32473-bar:foo"})
+
+	assertPattern(t, "urn:dev:os:324??-*",
+		[]string{"urn:dev:os:32473-5002", "urn:dev:os:32400-12-34"},
+		[]string{"urn:dev:os:3247-5002", "urn:dev:os:32473-5002_component"})
+}
+
+func TestPatternOther(t *testing.T) {
+	assertPattern(t, "urn:dev:example:*:bar_*",
+		[]string{"urn:dev:example:foo:bar_baz", "urn:dev:example:zoo:bar_a_b"},
+		[]string{"urn:dev:example:foo:bar", "urn:dev:example:foo:baz_baz", "urn:dev:other:foo:bar_baz"})
+}
+
+func TestParsePatternInvalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"urn:dev:",
+		"urn:dev:mac",
+		"urn:foo:mac:*",
+		"urn:dev:*:foo",
+		"urn:dev:mac:0024BE*",
+		"urn:dev:mac:0024be*_",
+		"urn:dev:mac:0024be*_[a]",
+		"urn:dev:org:*",
+		"urn:dev:org:abc-foo",
+		"urn:dev:org:[10-1]-foo",
+		"urn:dev:org:[0-10]-foo",
+		"urn:dev:org:[1-10-foo",
+		"urn:dev:org:[1-10]foo",
+		"urn:dev:org:[1-10]-",
+		"urn:dev:os:32473-",
+		"urn:dev:os:32473-[a]",
+		"urn:dev:ops:32473-Refrigerator",
+		"urn:dev:ops:32473-Refrigerator-5002-1",
+		"urn:dev:example:fo/o",
+	} {
+		value, err := ParsePattern(input)
+		assert.Error(t, err, input)
+		assert.Equal(t, Pattern{}, value, input)
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	var patterns []Pattern
+	for _, input := range []string{
+		"urn:dev:ops:32473-Refrigerator-*",
+		"urn:dev:ops:[1-40000]-*-*",
+		"urn:dev:mac:0024befffe804ff1_*",
+		"urn:dev:ops:32473-*-*",
+		"urn:dev:mac:*",
+		"urn:dev:example:foo",
+	} {
+		pattern, err := ParsePattern(input)
+		if err != nil {
+			t.Fatalf("Failed to parse %s", input)
+			return
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	matcher := NewMatcher(patterns...)
+
+	for _, test := range []struct {
+		name  string
+		index int
+	}{
+		{"urn:dev:ops:32473-Refrigerator-5002", 0},
+		{"urn:dev:ops:32473-Freezer-5002", 1},
+		{"urn:dev:ops:50000-Freezer-5002", -1},
+		{"urn:dev:mac:0024befffe804ff1_eth0", 2},
+		{"urn:dev:mac:0024befffe804ff1", 4},
+		{"urn:dev:mac:0024befffe804ff2_eth0", -1},
+		{"urn:dev:example:foo", 5},
+		{"urn:dev:example:bar", -1},
+		{"urn:dev:ow:10e2073a01080063", -1},
+	} {
+		devUrn, err := Parse(test.name)
+		if err != nil {
+			t.Fatalf("Failed to parse %s", test.name)
+			return
+		}
+		assert.Equal(t, test.index, matcher.First(devUrn), test.name)
+		assert.Equal(t, test.index >= 0, matcher.Match(devUrn), test.name)
+	}
+
+	assert.False(t, NewMatcher().Match(UrnDev{}))
+}
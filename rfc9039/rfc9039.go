@@ -261,3 +261,104 @@ func Parse(name string) (UrnDev, error) {
 
 	return out, nil
 }
+
+// formatName builds urn:dev string from parsed fields of devUrn. FullName is not used. Output needs to be checked with Parse as fields are not validated.
+func formatName(devUrn UrnDev) string {
+	var sections []string
+
+	switch devUrn.Subtype {
+	case "mac":
+		sections = append([]string{devUrn.Eui64Identifier}, devUrn.Identifier...)
+	case "ow":
+		sections = append([]string{devUrn.OwIdentifier}, devUrn.Identifier...)
+	case "org":
+		// Organization and the first identifier are separated by a dash
+		sections = append([]string{devUrn.Organization}, devUrn.Identifier...)
+		if len(sections) > 1 {
+			sections = append([]string{sections[0] + "-" + sections[1]}, sections[2:]...)
+		}
+	case "os":
+		sections = append([]string{devUrn.Organization + "-" + devUrn.Serial}, devUrn.Identifier...)
+	case "ops":
+		sections = append([]string{devUrn.Organization + "-" + devUrn.Product + "-" + devUrn.Serial}, devUrn.Identifier...)
+	default:
+		sections = devUrn.Identifier
+	}
+
+	name := UrnDevPrefix + devUrn.Subtype + ":" + strings.Join(sections, ":")
+
+	for _, component := range devUrn.Component {
+		name += "_" + component
+	}
+
+	return name
+}
+
+// compareLengths orders shorter length first.
+func compareLengths(a int, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareNumbers compares two decimal numbers without leading zeros.
+func compareNumbers(a string, b string) int {
+	if c := compareLengths(len(a), len(b)); c != 0 {
+		return c
+	}
+
+	return strings.Compare(a, b)
+}
+
+// compareLists compares lists of strings element by element, a list which is a prefix of the other list is ordered first.
+func compareLists(a []string, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := strings.Compare(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+
+	return compareLengths(len(a), len(b))
+}
+
+// Compare orders urn:dev names by subtype, then numerically by organization, then by product, serial, EUI-64 or 1-Wire address, identifiers and components. Strings are compared byte by byte and lists element by element, so a device is ordered directly before its components and a component before its subcomponents. Names with equal fields are ordered by FullName. Result is -1 if a is ordered before b, 1 if a is ordered after b and 0 if they are equal, which makes Compare suitable for slices.SortFunc. For names of the same subtype the order matches byte order of MarshalBinary encodings.
+func Compare(a UrnDev, b UrnDev) int {
+	if c := strings.Compare(a.Subtype, b.Subtype); c != 0 {
+		return c
+	}
+
+	if c := compareNumbers(a.Organization, b.Organization); c != 0 {
+		return c
+	}
+
+	if c := strings.Compare(a.Product, b.Product); c != 0 {
+		return c
+	}
+
+	if c := strings.Compare(a.Serial, b.Serial); c != 0 {
+		return c
+	}
+
+	if c := strings.Compare(a.Eui64Identifier, b.Eui64Identifier); c != 0 {
+		return c
+	}
+
+	if c := strings.Compare(a.OwIdentifier, b.OwIdentifier); c != 0 {
+		return c
+	}
+
+	if c := compareLists(a.Identifier, b.Identifier); c != 0 {
+		return c
+	}
+
+	if c := compareLists(a.Component, b.Component); c != 0 {
+		return c
+	}
+
+	return strings.Compare(a.FullName, b.FullName)
+}
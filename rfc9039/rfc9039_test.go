@@ -5,6 +5,7 @@ package rfc9039
 import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"slices"
 	"testing"
 )
 
@@ -25,6 +26,23 @@ func ExampleParse() {
 	// 5002
 }
 
+func ExampleCompare() {
+	var values []UrnDev
+	for _, name := range []string{"urn:dev:org:1000-foo", "urn:dev:org:10-foo_b", "urn:dev:org:10-foo:bar", "urn:dev:org:10-foo"} {
+		devUrn, _ := Parse(name)
+		values = append(values, devUrn)
+	}
+
+	slices.SortFunc(values, Compare)
+	for _, devUrn := range values {
+		fmt.Println(devUrn.FullName)
+	}
+	// Output: urn:dev:org:10-foo
+	// urn:dev:org:10-foo_b
+	// urn:dev:org:10-foo:bar
+	// urn:dev:org:1000-foo
+}
+
 func assertEmptyUrnDevStruct(t *testing.T, value UrnDev) {
 	assert.Equal(t, "", value.FullName)
 	assert.Equal(t, "", value.Subtype)
@@ -690,3 +708,49 @@ func TestUrnDevInvalidSubType(t *testing.T) {
 
 	assertEmptyUrnDevStruct(t, value)
 }
+
+func TestCompare(t *testing.T) {
+	// Names in expected order
+	names := []string{
+		"urn:dev:example:foo",
+		"urn:dev:mac:0024befffe804ff1",
+		"urn:dev:mac:0024befffe804ff1_eth0",
+		"urn:dev:mac:0024befffe804ff2",
+		"urn:dev:ops:10-Refrigerator-5002",
+		"urn:dev:ops:1000-Refrigerator-5002",
+		"urn:dev:ops:1000-Refrigerator-5002_compressor",
+		"urn:dev:ops:1000-Refrigerator-5002_compressor_motor",
+		"urn:dev:ops:1000-Refrigerator-5002_fan",
+		"urn:dev:ops:1000-Refrigerator-5003",
+		"urn:dev:org:9-foo",
+		"urn:dev:org:32473-foo",
+		"urn:dev:org:32473-foo:bar",
+		"urn:dev:os:32473-5002",
+		"urn:dev:ow:10e2073a01080063",
+		"URN:DEV:ow:264437f5000000ed",
+		"urn:dev:ow:264437f5000000ed",
+	}
+
+	var values []UrnDev
+	for _, name := range names {
+		devUrn, err := Parse(name)
+		if err != nil {
+			t.Fatalf("Failed to parse %s", name)
+			return
+		}
+		values = append(values, devUrn)
+	}
+
+	for i := range values {
+		assert.Equal(t, 0, Compare(values[i], values[i]), names[i])
+		for j := i + 1; j < len(values); j++ {
+			assert.Equal(t, -1, Compare(values[i], values[j]), names[i]+" < "+names[j])
+			assert.Equal(t, 1, Compare(values[j], values[i]), names[j]+" > "+names[i])
+		}
+	}
+
+	sorted := slices.Clone(values)
+	slices.Reverse(sorted)
+	slices.SortFunc(sorted, Compare)
+	assert.Equal(t, values, sorted)
+}
@@ -0,0 +1,408 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package senml provides tools for resolving RFC 8428 SenML records and grouping them by urn:dev device.
+package senml
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/internal/cbor"
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+const NameRegEx = "^[A-Za-z0-9][A-Za-z0-9\\-:\\./_]*$"
+
+// CBOR labels from RFC 8428 Section 6
+const (
+	LabelBaseVersion = -1
+	LabelBaseName    = -2
+	LabelBaseTime    = -3
+	LabelBaseUnit    = -4
+	LabelBaseValue   = -5
+	LabelBaseSum     = -6
+	LabelName        = 0
+	LabelUnit        = 1
+	LabelValue       = 2
+	LabelStringValue = 3
+	LabelBoolValue   = 4
+	LabelSum         = 5
+	LabelTime        = 6
+	LabelUpdateTime  = 7
+	LabelDataValue   = 8
+)
+
+// relativeTimeLimit is the time value below which SenML times are relative to current time.
+const relativeTimeLimit = 1 << 28
+
+// now is used for resolving relative times and can be replaced in tests.
+var now = time.Now
+
+// Record captures single SenML record as it was transferred. Base fields are pointers, or empty strings, when not present in the record.
+type Record struct {
+	BaseName    string
+	BaseTime    *float64
+	BaseUnit    string
+	BaseValue   *float64
+	BaseSum     *float64
+	BaseVersion *int64
+	Name        string
+	Unit        string
+	Value       *float64
+	StringValue *string
+	BoolValue   *bool
+	DataValue   []byte
+	Sum         *float64
+	Time        float64
+	UpdateTime  float64
+}
+
+// Measurement captures resolved SenML record.
+type Measurement struct {
+	// Name is the resolved name, concatenation of base name and name.
+	Name string
+	// Device captures the urn:dev part of the name. Zero value when name does not start with urn:dev.
+	Device rfc9039.UrnDev
+	// Suffix captures the part of the name after the urn:dev device part and its separator, e.g. "temp" for "urn:dev:ow:10e2073a01080063:temp".
+	Suffix string
+	Unit   string
+	// Time is the absolute time of the measurement.
+	Time        time.Time
+	UpdateTime  float64
+	Value       *float64
+	StringValue *string
+	BoolValue   *bool
+	DataValue   []byte
+	Sum         *float64
+}
+
+// Group captures measurements of single device.
+type Group struct {
+	// Device is the urn:dev of the device. Zero value for measurements whose name does not start with urn:dev.
+	Device       rfc9039.UrnDev
+	Measurements []Measurement
+}
+
+type jsonRecord struct {
+	BaseName    string   `json:"bn"`
+	BaseTime    *float64 `json:"bt"`
+	BaseUnit    string   `json:"bu"`
+	BaseValue   *float64 `json:"bv"`
+	BaseSum     *float64 `json:"bs"`
+	BaseVersion *int64   `json:"bver"`
+	Name        string   `json:"n"`
+	Unit        string   `json:"u"`
+	Value       *float64 `json:"v"`
+	StringValue *string  `json:"vs"`
+	BoolValue   *bool    `json:"vb"`
+	DataValue   *string  `json:"vd"`
+	Sum         *float64 `json:"s"`
+	Time        float64  `json:"t"`
+	UpdateTime  float64  `json:"ut"`
+}
+
+// ParseJSON parses SenML JSON pack. Unknown fields are ignored unless their name ends with "_" which marks them mandatory to understand, in which case an error is returned.
+func ParseJSON(data []byte) ([]Record, error) {
+	var fields []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, errors.New("invalid input (json)")
+	}
+
+	for _, field := range fields {
+		for key := range field {
+			if strings.HasSuffix(key, "_") {
+				return nil, errors.New("invalid input (unsupported field " + key + ")")
+			}
+		}
+	}
+
+	var records []jsonRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, errors.New("invalid input (json)")
+	}
+
+	out := []Record{}
+	for _, record := range records {
+		value := Record{
+			BaseName:    record.BaseName,
+			BaseTime:    record.BaseTime,
+			BaseUnit:    record.BaseUnit,
+			BaseValue:   record.BaseValue,
+			BaseSum:     record.BaseSum,
+			BaseVersion: record.BaseVersion,
+			Name:        record.Name,
+			Unit:        record.Unit,
+			Value:       record.Value,
+			StringValue: record.StringValue,
+			BoolValue:   record.BoolValue,
+			Sum:         record.Sum,
+			Time:        record.Time,
+			UpdateTime:  record.UpdateTime,
+		}
+
+		if record.DataValue != nil {
+			// Data values are base64url encoded with padding omitted
+			dataValue, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(*record.DataValue, "="))
+			if err != nil {
+				return nil, errors.New("invalid input (vd)")
+			}
+			value.DataValue = dataValue
+		}
+
+		out = append(out, value)
+	}
+
+	return out, nil
+}
+
+func cborNumber(value any) (float64, bool) {
+	switch number := value.(type) {
+	case int64:
+		return float64(number), true
+	case float64:
+		return number, true
+	default:
+		return 0, false
+	}
+}
+
+// ParseCBOR parses SenML CBOR pack. Unknown labels are ignored unless they are text strings ending with "_" which marks them mandatory to understand, in which case an error is returned.
+func ParseCBOR(data []byte) ([]Record, error) {
+	pack, err := cbor.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	items, ok := pack.([]any)
+	if !ok {
+		return nil, errors.New("invalid input (pack)")
+	}
+
+	out := []Record{}
+	for _, item := range items {
+		fields, ok := item.(map[any]any)
+		if !ok {
+			return nil, errors.New("invalid input (record)")
+		}
+
+		record := Record{}
+		for key, value := range fields {
+			label, ok := key.(int64)
+			if !ok {
+				if strings.HasSuffix(key.(string), "_") {
+					return nil, errors.New("invalid input (unsupported field " + key.(string) + ")")
+				}
+				continue
+			}
+
+			var valid bool
+			switch label {
+			case LabelBaseName, LabelName, LabelBaseUnit, LabelUnit, LabelStringValue:
+				var text string
+				text, valid = value.(string)
+				switch label {
+				case LabelBaseName:
+					record.BaseName = text
+				case LabelName:
+					record.Name = text
+				case LabelBaseUnit:
+					record.BaseUnit = text
+				case LabelUnit:
+					record.Unit = text
+				default:
+					record.StringValue = &text
+				}
+
+			case LabelBaseTime, LabelBaseValue, LabelBaseSum, LabelValue, LabelSum, LabelTime, LabelUpdateTime:
+				var number float64
+				number, valid = cborNumber(value)
+				switch label {
+				case LabelBaseTime:
+					record.BaseTime = &number
+				case LabelBaseValue:
+					record.BaseValue = &number
+				case LabelBaseSum:
+					record.BaseSum = &number
+				case LabelValue:
+					record.Value = &number
+				case LabelSum:
+					record.Sum = &number
+				case LabelTime:
+					record.Time = number
+				default:
+					record.UpdateTime = number
+				}
+
+			case LabelBaseVersion:
+				var version int64
+				version, valid = value.(int64)
+				record.BaseVersion = &version
+
+			case LabelBoolValue:
+				var flag bool
+				flag, valid = value.(bool)
+				record.BoolValue = &flag
+
+			case LabelDataValue:
+				record.DataValue, valid = value.([]byte)
+
+			default:
+				valid = true
+			}
+
+			if !valid {
+				return nil, errors.New("invalid input (record)")
+			}
+		}
+
+		out = append(out, record)
+	}
+
+	return out, nil
+}
+
+func isValidName(name string) bool {
+	match, _ := regexp.MatchString(NameRegEx, name)
+
+	return match
+}
+
+// resolveTime converts SenML time to absolute time. Values below 2**28 are relative to current time.
+func resolveTime(value float64) time.Time {
+	if value < relativeTimeLimit {
+		return now().Add(time.Duration(value * float64(time.Second))).UTC()
+	}
+
+	sec, frac := math.Modf(value)
+
+	return time.Unix(int64(sec), int64(frac*1e9)).UTC()
+}
+
+// Resolve resolves records into measurements as specified in RFC 8428 Section 4.6: base name is prepended to name, base time is added to time, base unit is used when unit is missing and base value and base sum are added to value and sum. Resolved names starting with urn:dev are split into device and suffix with SplitName. An error is returned if resolved name is not valid, urn:dev part of the name cannot be parsed or record does not carry any value.
+func Resolve(records []Record) ([]Measurement, error) {
+	out := []Measurement{}
+
+	var baseName, baseUnit string
+	var baseTime, baseValue, baseSum float64
+	var hasBaseValue, hasBaseSum bool
+
+	for _, record := range records {
+		if record.BaseName != "" {
+			baseName = record.BaseName
+		}
+		if record.BaseUnit != "" {
+			baseUnit = record.BaseUnit
+		}
+		if record.BaseTime != nil {
+			baseTime = *record.BaseTime
+		}
+		if record.BaseValue != nil {
+			baseValue, hasBaseValue = *record.BaseValue, true
+		}
+		if record.BaseSum != nil {
+			baseSum, hasBaseSum = *record.BaseSum, true
+		}
+
+		measurement := Measurement{
+			Name:        baseName + record.Name,
+			Unit:        record.Unit,
+			Time:        resolveTime(baseTime + record.Time),
+			UpdateTime:  record.UpdateTime,
+			StringValue: record.StringValue,
+			BoolValue:   record.BoolValue,
+			DataValue:   record.DataValue,
+		}
+
+		if !isValidName(measurement.Name) {
+			return nil, errors.New("invalid input (name)")
+		}
+
+		if measurement.Unit == "" {
+			measurement.Unit = baseUnit
+		}
+
+		hasOtherValue := record.StringValue != nil || record.BoolValue != nil || record.DataValue != nil
+		if record.Value != nil || (hasBaseValue && !hasOtherValue) {
+			value := baseValue
+			if record.Value != nil {
+				value += *record.Value
+			}
+			measurement.Value = &value
+		}
+
+		if record.Sum != nil || hasBaseSum {
+			sum := baseSum
+			if record.Sum != nil {
+				sum += *record.Sum
+			}
+			measurement.Sum = &sum
+		}
+
+		if measurement.Value == nil && measurement.Sum == nil && !hasOtherValue {
+			return nil, errors.New("invalid input (no value)")
+		}
+
+		if rfc9039.HasUrnDevPrefix(measurement.Name) {
+			var err error
+			measurement.Device, measurement.Suffix, err = SplitName(measurement.Name)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		out = append(out, measurement)
+	}
+
+	return out, nil
+}
+
+// SplitName splits resolved SenML name into urn:dev device part and measurement suffix. Name is split at ":" or "/" separator and the shortest leading part which parses with rfc9039.Parse is chosen as the device, e.g. "urn:dev:ow:10e2073a01080063:voltage" is split into "urn:dev:ow:10e2073a01080063" and "voltage". Components stay in the device part. Suffix is empty when the whole name is the device. Note that for subtypes allowing additional identifiers, e.g. "org" and "os", trailing identifiers of the device cannot be told apart from the suffix.
+func SplitName(name string) (rfc9039.UrnDev, string, error) {
+	if !rfc9039.HasUrnDevPrefix(name) {
+		return rfc9039.UrnDev{}, "", errors.New("invalid input (missing urn:dev)")
+	}
+
+	for i := len(rfc9039.UrnDevPrefix); i <= len(name); i++ {
+		if i < len(name) && name[i] != ':' && name[i] != '/' {
+			continue
+		}
+
+		devUrn, err := rfc9039.Parse(name[:i])
+		if err != nil {
+			continue
+		}
+
+		suffix := ""
+		if i < len(name) {
+			suffix = name[i+1:]
+		}
+
+		return devUrn, suffix, nil
+	}
+
+	return rfc9039.UrnDev{}, "", errors.New("invalid input (urn:dev)")
+}
+
+// GroupByDevice groups measurements by device urn:dev in the order devices first appear. Measurements of device components are grouped under the component name. Measurements whose name does not start with urn:dev are grouped together under zero value Device.
+func GroupByDevice(measurements []Measurement) []Group {
+	out := []Group{}
+	index := map[string]int{}
+
+	for _, measurement := range measurements {
+		i, ok := index[measurement.Device.FullName]
+		if !ok {
+			i = len(out)
+			index[measurement.Device.FullName] = i
+			out = append(out, Group{Device: measurement.Device, Measurements: []Measurement{}})
+		}
+
+		out[i].Measurements = append(out[i].Measurements, measurement)
+	}
+
+	return out
+}
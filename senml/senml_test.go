@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package senml
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func ExampleGroupByDevice() {
+	records, _ := ParseJSON([]byte(`[
+		{"bn":"urn:dev:ow:10e2073a01080063:","n":"voltage","u":"V","v":120.1},
+		{"n":"current","u":"A","v":1.2},
+		{"bn":"urn:dev:ow:10e2073a01080064:","n":"voltage","u":"V","v":119.7}
+	]`))
+	measurements, _ := Resolve(records)
+	for _, group := range GroupByDevice(measurements) {
+		fmt.Println(group.Device.OwIdentifier, len(group.Measurements), group.Measurements[0].Suffix)
+	}
+	// Output: 10e2073a01080063 2 voltage
+	// 10e2073a01080064 1 voltage
+}
+
+func TestParseJSON(t *testing.T) {
+	// RFC 8428 Section 5.1.3
+	records, err := ParseJSON([]byte(`[
+		{"bn":"urn:dev:ow:10e2073a01080063:","bt":1.276020076001e+09,"bu":"A","bver":5,"n":"voltage","u":"V","v":120.1},
+		{"n":"current","t":-5,"v":1.2},
+		{"n":"current","t":-4,"v":1.3},
+		{"n":"current","t":-3,"v":1.4},
+		{"n":"current","t":-2,"v":1.5},
+		{"n":"current","t":-1,"v":1.6},
+		{"n":"current","v":1.7}
+	]`))
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, 7, len(records))
+	assert.Equal(t, int64(5), *records[0].BaseVersion)
+
+	measurements, err := Resolve(records)
+	if err != nil {
+		t.Fatalf("Failed to resolve: %v", err)
+		return
+	}
+	assert.Equal(t, "urn:dev:ow:10e2073a01080063:voltage", measurements[0].Name)
+	assert.Equal(t, "V", measurements[0].Unit)
+	assert.Equal(t, "urn:dev:ow:10e2073a01080063:current", measurements[1].Name)
+	assert.Equal(t, "A", measurements[1].Unit)
+	assert.Equal(t, "current", measurements[1].Suffix)
+	assert.Equal(t, "10e2073a01080063", measurements[1].Device.OwIdentifier)
+	assert.Equal(t, 1.2, *measurements[1].Value)
+	assert.Equal(t, time.Unix(1276020071, 1000000).UTC(), measurements[1].Time.Round(time.Millisecond))
+	assert.Nil(t, measurements[1].Sum)
+
+	groups := GroupByDevice(measurements)
+	assert.Equal(t, 1, len(groups))
+	assert.Equal(t, "urn:dev:ow:10e2073a01080063", groups[0].Device.FullName)
+	assert.Equal(t, 7, len(groups[0].Measurements))
+}
+
+func TestParseCBOR(t *testing.T) {
+	// [{-2: "urn:dev:ow:10e2073a01080063:", -3: 1320067464.0, 0: "voltage", 1: "V", 2: 120.1},
+	//  {0: "current", 1: "A", 2: 1.2},
+	//  {-2: "urn:dev:mac:0024befffe804ff1_eth0:", 0: "up", 4: true},
+	//  {0: "fw", 8: h'0102'}]
+	data, _ := hex.DecodeString("84a521781c75726e3a6465763a6f773a313065323037336130313038303036333a22fb41d3aba8620000000067766f6c7461676501615602fb405e066666666666a3006763757272656e7401614102fb3ff3333333333333a321782275726e3a6465763a6d61633a303032346265666666653830346666315f657468303a0062757004f5a20062667708420102")
+
+	records, err := ParseCBOR(data)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+		return
+	}
+
+	measurements, err := Resolve(records)
+	if err != nil {
+		t.Fatalf("Failed to resolve: %v", err)
+		return
+	}
+	assert.Equal(t, 4, len(measurements))
+	assert.Equal(t, 120.1, *measurements[0].Value)
+	assert.Equal(t, time.Unix(1320067464, 0).UTC(), measurements[1].Time)
+	assert.Equal(t, "urn:dev:mac:0024befffe804ff1_eth0:up", measurements[2].Name)
+	assert.Equal(t, "0024befffe804ff1", measurements[2].Device.Eui64Identifier)
+	assert.Equal(t, []string{"eth0"}, measurements[2].Device.Component)
+	assert.Equal(t, "up", measurements[2].Suffix)
+	assert.True(t, *measurements[2].BoolValue)
+	assert.Equal(t, []byte{1, 2}, measurements[3].DataValue)
+	assert.Nil(t, measurements[3].Value)
+
+	groups := GroupByDevice(measurements)
+	assert.Equal(t, 2, len(groups))
+	assert.Equal(t, "urn:dev:ow:10e2073a01080063", groups[0].Device.FullName)
+	assert.Equal(t, "urn:dev:mac:0024befffe804ff1_eth0", groups[1].Device.FullName)
+	assert.Equal(t, 2, len(groups[1].Measurements))
+}
+
+func TestResolveBaseValues(t *testing.T) {
+	defer func() { now = time.Now }()
+	now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	records, err := ParseJSON([]byte(`[
+		{"bn":"urn:dev:ops:32473-Refrigerator-5002","bv":10,"bs":100,"v":1,"t":-60},
+		{"bn":"sensor-","n":"1","s":5,"vd":"AQI"},
+		{"bn":"urn:dev:os:32473-5002/","bv":0,"n":"door","vs":"open"}
+	]`))
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+
+	measurements, err := Resolve(records)
+	if err != nil {
+		t.Fatalf("Failed to resolve: %v", err)
+		return
+	}
+	assert.Equal(t, "", measurements[0].Suffix)
+	assert.Equal(t, "urn:dev:ops:32473-Refrigerator-5002", measurements[0].Device.FullName)
+	assert.Equal(t, 11.0, *measurements[0].Value)
+	assert.Equal(t, 100.0, *measurements[0].Sum)
+	assert.Equal(t, time.Date(2025, 12, 31, 23, 59, 0, 0, time.UTC), measurements[0].Time)
+
+	assert.Equal(t, "sensor-1", measurements[1].Name)
+	assert.Equal(t, "", measurements[1].Device.FullName)
+	assert.Equal(t, 105.0, *measurements[1].Sum)
+	assert.Nil(t, measurements[1].Value)
+	assert.Equal(t, []byte{1, 2}, measurements[1].DataValue)
+
+	assert.Equal(t, "door", measurements[2].Suffix)
+	assert.Equal(t, "5002", measurements[2].Device.Serial)
+	assert.Equal(t, "open", *measurements[2].StringValue)
+	assert.Nil(t, measurements[2].Value)
+
+	groups := GroupByDevice(measurements)
+	assert.Equal(t, 3, len(groups))
+	assert.Equal(t, "", groups[1].Device.FullName)
+}
+
+func TestSplitName(t *testing.T) {
+	device, suffix, err := SplitName("urn:dev:org:32473-foo:temp")
+	assert.NoError(t, err)
+	assert.Equal(t, "urn:dev:org:32473-foo", device.FullName)
+	assert.Equal(t, "temp", suffix)
+
+	device, suffix, err = SplitName("urn:dev:ow:264437f5000000ed_humidity:value:max")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"humidity"}, device.Component)
+	assert.Equal(t, "value:max", suffix)
+
+	for _, input := range []string{
+		"",
+		"sensor",
+		"urn:dev:mac:0024befffe804ff1temp",
+		"urn:dev:mac:",
+		"urn:dev:ops:32473-Refrigerator:temp",
+	} {
+		device, suffix, err := SplitName(input)
+		assert.Error(t, err, input)
+		assert.Equal(t, "", device.FullName)
+		assert.Equal(t, "", suffix)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, input := range []string{
+		``,
+		`{}`,
+		`[{"n":1}]`,
+		`[{"n":"a","v":1,"x_":1}]`,
+		`[{"n":"a","vd":"#"}]`,
+	} {
+		_, err := ParseJSON([]byte(input))
+		assert.Error(t, err, input)
+	}
+
+	for _, input := range []string{
+		"",
+		"a0",
+		"81a10001",
+		"81a1026161",
+		"81a162785f01",
+		"81a10442aaaa",
+	} {
+		data, _ := hex.DecodeString(input)
+		_, err := ParseCBOR(data)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestResolveInvalid(t *testing.T) {
+	for _, input := range []string{
+		`[{"v":1}]`,
+		`[{"n":"-a","v":1}]`,
+		`[{"n":"a b","v":1}]`,
+		`[{"n":"a"}]`,
+		`[{"n":"urn:dev:mac:0024befffe804ff:temp","v":1}]`,
+	} {
+		records, err := ParseJSON([]byte(input))
+		if err != nil {
+			t.Fatalf("Failed to parse %s", input)
+			return
+		}
+
+		measurements, err := Resolve(records)
+		assert.Error(t, err, input)
+		assert.Nil(t, measurements)
+	}
+}
@@ -0,0 +1,331 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package smbios provides tools for parsing SMBIOS (DMI) entry point and structure table and mapping Type 1 system information into urn:dev identifiers.
+package smbios
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strings"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+// Anchor strings of 32-bit (SMBIOS 2.1) and 64-bit (SMBIOS 3.0) entry points.
+const (
+	Anchor32 = "_SM_"
+	Anchor64 = "_SM3_"
+)
+
+// Structure types used by the package.
+const (
+	TypeSystem     = 1
+	TypeEndOfTable = 127
+)
+
+// Sysfs files holding entry point and table relative to /sys.
+const (
+	SysfsEntryPoint = "firmware/dmi/tables/smbios_entry_point"
+	SysfsTable      = "firmware/dmi/tables/DMI"
+)
+
+// DefaultManufacturers maps common Type 1 manufacturer strings, in lower case, to their IANA Private Enterprise Numbers.
+var DefaultManufacturers = map[string]int{
+	"cisco":                      9,
+	"cisco systems inc":          9,
+	"dell":                       674,
+	"dell inc.":                  674,
+	"hewlett packard enterprise": 47196,
+	"hewlett-packard":            11,
+	"hp":                         11,
+	"hpe":                        47196,
+	"huawei":                     2011,
+	"ibm":                        2,
+	"intel corporation":          343,
+	"lenovo":                     19046,
+	"oracle corporation":         111,
+	"sun microsystems":           42,
+	"super micro computer, inc.": 10876,
+	"supermicro":                 10876,
+}
+
+// EntryPoint captures fields of SMBIOS entry point needed for locating and interpreting the structure table.
+type EntryPoint struct {
+	// Major is the major version of SMBIOS specification implemented.
+	Major uint8
+	// Minor is the minor version of SMBIOS specification implemented.
+	Minor uint8
+	// TableAddress is the physical address of the structure table, or offset of it in dump files.
+	TableAddress uint64
+	// TableLength is the length of the structure table, maximum length with 64-bit entry point.
+	TableLength uint32
+}
+
+// Structure captures single SMBIOS structure.
+type Structure struct {
+	// Type is the structure type.
+	Type uint8
+	// Handle is the structure handle.
+	Handle uint16
+	// Formatted holds the formatted area including the 4 byte header.
+	Formatted []byte
+	// Strings holds the strings following the formatted area.
+	Strings []string
+}
+
+// Table captures SMBIOS structure table.
+type Table struct {
+	// EntryPoint is the entry point the table was located with.
+	EntryPoint EntryPoint
+	// Structures holds the structures before end-of-table structure.
+	Structures []Structure
+}
+
+// System captures SMBIOS Type 1 system information.
+type System struct {
+	Manufacturer string
+	ProductName  string
+	Version      string
+	SerialNumber string
+	// UUID is the system UUID in lower case "4c4c4544-0042-3510-8052-b4c04f4a4d32" form, or empty when not present or not set.
+	UUID   string
+	SKU    string
+	Family string
+}
+
+func checksum(data []byte) bool {
+	sum := byte(0)
+	for _, value := range data {
+		sum += value
+	}
+
+	return sum == 0
+}
+
+// ParseEntryPoint parses 32-bit "_SM_" or 64-bit "_SM3_" entry point as found in sysfs smbios_entry_point file or at the start of dmidecode --dump-bin output. If incorrectly formed entry point is given as input, e.g. with invalid checksum, an error is returned.
+func ParseEntryPoint(data []byte) (EntryPoint, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte(Anchor64)):
+		if len(data) < 0x18 || int(data[6]) < 0x18 || len(data) < int(data[6]) || !checksum(data[:data[6]]) {
+			return EntryPoint{}, errors.New("invalid input (entry point)")
+		}
+
+		return EntryPoint{
+			Major:        data[7],
+			Minor:        data[8],
+			TableLength:  binary.LittleEndian.Uint32(data[0x0c:]),
+			TableAddress: binary.LittleEndian.Uint64(data[0x10:]),
+		}, nil
+
+	case bytes.HasPrefix(data, []byte(Anchor32)):
+		if len(data) < 0x1f || int(data[5]) < 0x1f || len(data) < int(data[5]) || !checksum(data[:data[5]]) {
+			return EntryPoint{}, errors.New("invalid input (entry point)")
+		}
+
+		if string(data[0x10:0x15]) != "_DMI_" || !checksum(data[0x10:0x1f]) {
+			return EntryPoint{}, errors.New("invalid input (entry point)")
+		}
+
+		return EntryPoint{
+			Major:        data[6],
+			Minor:        data[7],
+			TableLength:  uint32(binary.LittleEndian.Uint16(data[0x16:])),
+			TableAddress: uint64(binary.LittleEndian.Uint32(data[0x18:])),
+		}, nil
+
+	default:
+		return EntryPoint{}, errors.New("invalid input (entry point anchor)")
+	}
+}
+
+// ParseTable parses structure table data following entryPoint. Parsing stops at end-of-table structure or at the end of data. If incorrectly formed structure is found an error is returned.
+func ParseTable(entryPoint EntryPoint, data []byte) (Table, error) {
+	out := Table{EntryPoint: entryPoint, Structures: []Structure{}}
+
+	for offset := 0; offset+4 <= len(data); {
+		length := int(data[offset+1])
+		if length < 4 || offset+length > len(data) {
+			return Table{}, errors.New("invalid input (structure length)")
+		}
+
+		structure := Structure{
+			Type:      data[offset],
+			Handle:    binary.LittleEndian.Uint16(data[offset+2:]),
+			Formatted: data[offset : offset+length],
+			Strings:   []string{},
+		}
+
+		// Strings area ends with two zero bytes, also when there are no strings
+		end := bytes.Index(data[offset+length:], []byte{0, 0})
+		if end < 0 {
+			return Table{}, errors.New("invalid input (structure strings)")
+		}
+
+		if end > 0 {
+			for _, value := range strings.Split(string(data[offset+length:offset+length+end]), "\x00") {
+				structure.Strings = append(structure.Strings, value)
+			}
+		}
+
+		if structure.Type == TypeEndOfTable {
+			break
+		}
+
+		out.Structures = append(out.Structures, structure)
+		offset += length + end + 2
+	}
+
+	return out, nil
+}
+
+// ParseDump parses dmidecode --dump-bin output, which has entry point at the start and table at the offset given as table address.
+func ParseDump(data []byte) (Table, error) {
+	entryPoint, err := ParseEntryPoint(data)
+	if err != nil {
+		return Table{}, err
+	}
+
+	if entryPoint.TableAddress > uint64(len(data)) {
+		return Table{}, errors.New("invalid input (table address)")
+	}
+
+	table := data[entryPoint.TableAddress:]
+	if uint64(len(table)) > uint64(entryPoint.TableLength) {
+		table = table[:entryPoint.TableLength]
+	}
+
+	return ParseTable(entryPoint, table)
+}
+
+// ReadSysfs reads entry point and table from fsys rooted at /sys, e.g. os.DirFS("/sys"). The files are readable only by root.
+func ReadSysfs(fsys fs.FS) (Table, error) {
+	data, err := fs.ReadFile(fsys, SysfsEntryPoint)
+	if err != nil {
+		return Table{}, err
+	}
+
+	entryPoint, err := ParseEntryPoint(data)
+	if err != nil {
+		return Table{}, err
+	}
+
+	data, err = fs.ReadFile(fsys, SysfsTable)
+	if err != nil {
+		return Table{}, err
+	}
+
+	return ParseTable(entryPoint, data)
+}
+
+// Byte returns byte at offset of formatted area, or 0 when the structure is too short for it.
+func (s Structure) Byte(offset int) byte {
+	if offset >= len(s.Formatted) {
+		return 0
+	}
+
+	return s.Formatted[offset]
+}
+
+// String returns string referenced by byte at offset of formatted area with surrounding white space trimmed. Empty string is returned when there is no string.
+func (s Structure) String(offset int) string {
+	index := int(s.Byte(offset))
+	if index == 0 || index > len(s.Strings) {
+		return ""
+	}
+
+	return strings.TrimSpace(s.Strings[index-1])
+}
+
+// formatUUID formats UUID of Type 1 structure. Since SMBIOS 2.6 the first three fields are little endian, earlier versions are ambiguous and are taken as big endian as in RFC 4122.
+func formatUUID(data []byte, entryPoint EntryPoint) string {
+	if bytes.Equal(data, make([]byte, 16)) || bytes.Equal(data, bytes.Repeat([]byte{0xff}, 16)) {
+		return ""
+	}
+
+	uuid := append([]byte{}, data...)
+	if entryPoint.Major > 2 || (entryPoint.Major == 2 && entryPoint.Minor >= 6) {
+		uuid[0], uuid[1], uuid[2], uuid[3] = uuid[3], uuid[2], uuid[1], uuid[0]
+		uuid[4], uuid[5] = uuid[5], uuid[4]
+		uuid[6], uuid[7] = uuid[7], uuid[6]
+	}
+
+	text := hex.EncodeToString(uuid)
+
+	return fmt.Sprintf("%s-%s-%s-%s-%s", text[0:8], text[8:12], text[12:16], text[16:20], text[20:32])
+}
+
+// System returns Type 1 system information of the table. UUID is present since SMBIOS 2.1, SKU and Family since 2.4. If table does not have Type 1 structure an error is returned.
+func (t Table) System() (System, error) {
+	for _, structure := range t.Structures {
+		if structure.Type != TypeSystem {
+			continue
+		}
+
+		out := System{
+			Manufacturer: structure.String(0x04),
+			ProductName:  structure.String(0x05),
+			Version:      structure.String(0x06),
+			SerialNumber: structure.String(0x07),
+			SKU:          structure.String(0x19),
+			Family:       structure.String(0x1a),
+		}
+
+		if len(structure.Formatted) >= 0x18 {
+			out.UUID = formatUUID(structure.Formatted[0x08:0x18], t.EntryPoint)
+		}
+
+		return out, nil
+	}
+
+	return System{}, errors.New("invalid input (no system information)")
+}
+
+// placeholders are values firmware commonly leaves in fields which are not set. They are compared in lower case.
+var placeholders = map[string]bool{
+	"":                       true,
+	"0":                      true,
+	"0123456789":             true,
+	"default string":         true,
+	"none":                   true,
+	"not applicable":         true,
+	"not specified":          true,
+	"o.e.m.":                 true,
+	"system product name":    true,
+	"system serial number":   true,
+	"to be filled by o.e.m.": true,
+	"unknown":                true,
+}
+
+// identifierNoDash converts value into urn:dev identifier without dashes by replacing runs of other characters with ".". Placeholder values give an empty string.
+func identifierNoDash(value string) string {
+	if placeholders[strings.ToLower(value)] {
+		return ""
+	}
+
+	return strings.Trim(regexp.MustCompile("[^A-Za-z0-9\\.]+").ReplaceAllString(value, "."), ".")
+}
+
+// ToUrnDev maps system into urn:dev:ops identifier with Private Enterprise Number looked up from manufacturers by lower case Manufacturer, for example "urn:dev:ops:674-PowerEdge.R740-4ZC9FW2". DefaultManufacturers can be used or extended. Characters not valid in urn:dev product and serial, including "-", are replaced with ".". If manufacturer is not known or product name or serial number is not set an error is returned.
+func (s System) ToUrnDev(manufacturers map[string]int) (rfc9039.UrnDev, error) {
+	pen, ok := manufacturers[strings.ToLower(strings.TrimSpace(s.Manufacturer))]
+	if !ok || pen <= 0 {
+		return rfc9039.UrnDev{}, errors.New("invalid input (unknown manufacturer)")
+	}
+
+	product := identifierNoDash(s.ProductName)
+	if product == "" {
+		return rfc9039.UrnDev{}, errors.New("invalid input (product name)")
+	}
+
+	serial := identifierNoDash(s.SerialNumber)
+	if serial == "" {
+		return rfc9039.UrnDev{}, errors.New("invalid input (serial number)")
+	}
+
+	return rfc9039.Parse(fmt.Sprintf("%sops:%d-%s-%s", rfc9039.UrnDevPrefix, pen, product, serial))
+}
@@ -0,0 +1,204 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package smbios
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"testing/fstest"
+)
+
+// testUUID is Type 1 UUID field of a Dell system, shown by dmidecode as 4C4C4544-0042-3510-8052-B4C04F4A4D32.
+var testUUID = []byte{0x44, 0x45, 0x4c, 0x4c, 0x42, 0x00, 0x10, 0x35, 0x80, 0x52, 0xb4, 0xc0, 0x4f, 0x4a, 0x4d, 0x32}
+
+func fixChecksum(data []byte, index int) {
+	sum := byte(0)
+	for _, value := range data {
+		sum += value
+	}
+	data[index] -= sum
+}
+
+func entryPoint32(major byte, minor byte, tableLength int, tableAddress int) []byte {
+	data := make([]byte, 0x1f)
+	copy(data, Anchor32)
+	data[5] = 0x1f
+	data[6], data[7] = major, minor
+	copy(data[0x10:], "_DMI_")
+	binary.LittleEndian.PutUint16(data[0x16:], uint16(tableLength))
+	binary.LittleEndian.PutUint32(data[0x18:], uint32(tableAddress))
+	fixChecksum(data[0x10:0x1f], 0x05)
+	fixChecksum(data, 0x04)
+
+	return data
+}
+
+func entryPoint64(major byte, minor byte, tableLength int, tableAddress int) []byte {
+	data := make([]byte, 0x18)
+	copy(data, Anchor64)
+	data[6] = 0x18
+	data[7], data[8] = major, minor
+	data[0x0a] = 0x01
+	binary.LittleEndian.PutUint32(data[0x0c:], uint32(tableLength))
+	binary.LittleEndian.PutUint64(data[0x10:], uint64(tableAddress))
+	fixChecksum(data, 0x05)
+
+	return data
+}
+
+func structure(structureType byte, handle uint16, formatted []byte, strings ...string) []byte {
+	data := []byte{structureType, byte(4 + len(formatted)), byte(handle), byte(handle >> 8)}
+	data = append(data, formatted...)
+	for _, value := range strings {
+		data = append(data, value...)
+		data = append(data, 0)
+	}
+	if len(strings) == 0 {
+		data = append(data, 0)
+	}
+
+	return append(data, 0)
+}
+
+func testTable(uuid []byte) []byte {
+	system := append([]byte{1, 2, 3, 4}, uuid...)
+	system = append(system, 0x06, 5, 6)
+
+	data := structure(0, 0x0000, []byte{1, 2, 0x00, 0xf0}, "Dell Inc.", "2.15.1")
+	data = append(data, structure(TypeSystem, 0x0100, system, "Dell Inc.", "PowerEdge R740", "Not Specified", " 4ZC9FW2 ", "SKU=0715", "PowerEdge")...)
+	data = append(data, structure(TypeEndOfTable, 0xfeff, nil)...)
+
+	return data
+}
+
+func ExampleParseDump() {
+	table := testTable(testUUID)
+	dump := append(entryPoint32(2, 8, len(table), 0x20), 0)
+	dump = append(dump, table...)
+
+	parsed, _ := ParseDump(dump)
+	system, _ := parsed.System()
+	fmt.Println(system.Manufacturer, system.ProductName, system.SerialNumber, system.UUID)
+
+	devUrn, _ := system.ToUrnDev(DefaultManufacturers)
+	fmt.Println(devUrn.FullName)
+	// Output: Dell Inc. PowerEdge R740 4ZC9FW2 4c4c4544-0042-3510-8052-b4c04f4a4d32
+	// urn:dev:ops:674-PowerEdge.R740-4ZC9FW2
+}
+
+func TestParseEntryPoint(t *testing.T) {
+	entryPoint, err := ParseEntryPoint(entryPoint64(3, 2, 0x1234, 0x7a39e000))
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, EntryPoint{Major: 3, Minor: 2, TableLength: 0x1234, TableAddress: 0x7a39e000}, entryPoint)
+
+	entryPoint, err = ParseEntryPoint(entryPoint32(2, 7, 0x0a2c, 0x000f0000))
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, EntryPoint{Major: 2, Minor: 7, TableLength: 0x0a2c, TableAddress: 0x000f0000}, entryPoint)
+
+	corrupted := entryPoint32(2, 7, 0x0a2c, 0x000f0000)
+	corrupted[0x18]++
+
+	for _, input := range [][]byte{nil, []byte("_SM_"), entryPoint64(3, 2, 0, 0)[:0x10], corrupted, []byte("_DMI_ not an entry point")} {
+		_, err := ParseEntryPoint(input)
+		assert.NotNil(t, err, fmt.Sprintf("%x", input))
+	}
+}
+
+func TestReadSysfs(t *testing.T) {
+	sys := fstest.MapFS{
+		SysfsEntryPoint: &fstest.MapFile{Data: entryPoint64(3, 3, 0x1000, 0x7a39e000)},
+		SysfsTable:      &fstest.MapFile{Data: testTable(testUUID)},
+	}
+
+	table, err := ReadSysfs(sys)
+	if err != nil {
+		t.Fatalf("Failed to read")
+		return
+	}
+	assert.Equal(t, 2, len(table.Structures))
+	assert.Equal(t, uint16(0x0100), table.Structures[1].Handle)
+
+	system, err := table.System()
+	if err != nil {
+		t.Fatalf("Failed to parse system")
+		return
+	}
+	assert.Equal(t, System{
+		Manufacturer: "Dell Inc.",
+		ProductName:  "PowerEdge R740",
+		Version:      "Not Specified",
+		SerialNumber: "4ZC9FW2",
+		UUID:         "4c4c4544-0042-3510-8052-b4c04f4a4d32",
+		SKU:          "SKU=0715",
+		Family:       "PowerEdge",
+	}, system)
+
+	_, err = ReadSysfs(fstest.MapFS{})
+	assert.NotNil(t, err)
+}
+
+func TestSystemUUID(t *testing.T) {
+	// Before SMBIOS 2.6 UUID bytes are shown in order
+	table, _ := ParseTable(EntryPoint{Major: 2, Minor: 5}, testTable(testUUID))
+	system, _ := table.System()
+	assert.Equal(t, "44454c4c-4200-1035-8052-b4c04f4a4d32", system.UUID)
+
+	for _, uuid := range [][]byte{make([]byte, 16), {0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}} {
+		table, _ := ParseTable(EntryPoint{Major: 3}, testTable(uuid))
+		system, _ := table.System()
+		assert.Equal(t, "", system.UUID)
+	}
+
+	// SMBIOS 2.0 structure has no UUID
+	table, _ = ParseTable(EntryPoint{Major: 2}, structure(TypeSystem, 1, []byte{1, 2, 0, 0}, "HP", "ProLiant"))
+	system, _ = table.System()
+	assert.Equal(t, System{Manufacturer: "HP", ProductName: "ProLiant"}, system)
+}
+
+func TestParseTableInvalid(t *testing.T) {
+	valid := testTable(testUUID)
+
+	for _, input := range [][]byte{
+		{0x01, 0x02, 0x00, 0x00, 0x00, 0x00},
+		{0x01, 0x20, 0x00, 0x00},
+		valid[:30],
+	} {
+		_, err := ParseTable(EntryPoint{Major: 3}, input)
+		assert.NotNil(t, err, fmt.Sprintf("%x", input))
+	}
+
+	table, _ := ParseTable(EntryPoint{Major: 3}, structure(0, 0, nil))
+	_, err := table.System()
+	assert.NotNil(t, err)
+}
+
+func TestToUrnDev(t *testing.T) {
+	system := System{Manufacturer: "LENOVO", ProductName: "ThinkSystem SR650 -[7X06CTO1WW]-", SerialNumber: "J30-0ABC1"}
+	devUrn, err := system.ToUrnDev(DefaultManufacturers)
+	if err != nil {
+		t.Fatalf("Failed to map")
+		return
+	}
+	assert.Equal(t, "urn:dev:ops:19046-ThinkSystem.SR650.7X06CTO1WW-J30.0ABC1", devUrn.FullName)
+
+	manufacturers := map[string]int{"rising edge solutions": 32473}
+	devUrn, _ = System{Manufacturer: "Rising Edge Solutions", ProductName: "Gateway", SerialNumber: "5002"}.ToUrnDev(manufacturers)
+	assert.Equal(t, "urn:dev:ops:32473-Gateway-5002", devUrn.FullName)
+
+	for _, input := range []System{
+		{Manufacturer: "Unknown Vendor", ProductName: "Box", SerialNumber: "1"},
+		{Manufacturer: "Dell Inc.", ProductName: "PowerEdge R740", SerialNumber: "To Be Filled By O.E.M."},
+		{Manufacturer: "Dell Inc.", ProductName: "", SerialNumber: "4ZC9FW2"},
+	} {
+		_, err := input.ToUrnDev(DefaultManufacturers)
+		assert.NotNil(t, err, input.Manufacturer)
+	}
+}
@@ -0,0 +1,297 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package snmp provides tools for decoding and encoding SNMP engine identifiers (RFC 3411) and mapping them into urn:dev identifiers.
+package snmp
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+const EngineIDRegEx = "^(0x)?([0-9a-f]{2}(:?[0-9a-f]{2})*)$"
+
+// Engine ID length limits.
+const (
+	MinEngineIDLength    = 5
+	MaxEngineIDLength    = 32
+	LegacyEngineIDLength = 12
+)
+
+// Format defines the format of the value following enterprise number in engine ID.
+type Format uint8
+
+const (
+	// FormatLegacy is used for SNMPv1 style engine IDs whose first bit is not set. They have 8 octets of enterprise specific value and no format octet.
+	FormatLegacy Format = 0
+	FormatIPv4   Format = 1
+	FormatIPv6   Format = 2
+	FormatMAC    Format = 3
+	FormatText   Format = 4
+	FormatOctets Format = 5
+	// FormatEnterprise is the first enterprise specific format, formats 128 to 255 are enterprise specific.
+	FormatEnterprise Format = 128
+)
+
+// EngineID captures decoded snmpEngineID.
+type EngineID struct {
+	// Enterprise is the IANA Private Enterprise Number of the SNMP engine vendor.
+	Enterprise uint32
+	// Format is the format of Value.
+	Format Format
+	// Value is the value following enterprise number and format octet.
+	Value []byte
+}
+
+// String returns name of the format.
+func (f Format) String() string {
+	switch {
+	case f == FormatLegacy:
+		return "legacy"
+	case f == FormatIPv4:
+		return "ipv4"
+	case f == FormatIPv6:
+		return "ipv6"
+	case f == FormatMAC:
+		return "mac"
+	case f == FormatText:
+		return "text"
+	case f == FormatOctets:
+		return "octets"
+	case f >= FormatEnterprise:
+		return "enterprise" + strconv.Itoa(int(f))
+	default:
+		return "reserved"
+	}
+}
+
+// checkValue validates value length of format. Value must not be empty with any format, as empty value cannot be mapped into urn:dev identifier.
+func checkValue(format Format, value []byte) error {
+	switch {
+	case format == FormatLegacy && len(value) != LegacyEngineIDLength-4:
+		return errors.New("invalid input (legacy engine id length)")
+	case format == FormatIPv4 && len(value) != net.IPv4len:
+		return errors.New("invalid input (ipv4)")
+	case format == FormatIPv6 && len(value) != net.IPv6len:
+		return errors.New("invalid input (ipv6)")
+	case format == FormatMAC && len(value) != 6:
+		return errors.New("invalid input (mac)")
+	case format > FormatOctets && format < FormatEnterprise:
+		return errors.New("invalid input (reserved format)")
+	case len(value) < 1:
+		return errors.New("invalid input (empty value)")
+	case format != FormatLegacy && 5+len(value) > MaxEngineIDLength:
+		return errors.New("invalid input (engine id length)")
+	}
+
+	return nil
+}
+
+// Decode decodes engine ID from binary form. If incorrectly formed engine ID is given as input, e.g. with reserved format, empty value or value of wrong length, an error is returned.
+func Decode(data []byte) (EngineID, error) {
+	if len(data) < MinEngineIDLength || len(data) > MaxEngineIDLength {
+		return EngineID{}, errors.New("invalid input (engine id length)")
+	}
+
+	out := EngineID{Enterprise: binary.BigEndian.Uint32(data) & 0x7fffffff}
+
+	if data[0]&0x80 == 0 {
+		out.Format = FormatLegacy
+		out.Value = append([]byte{}, data[4:]...)
+	} else {
+		out.Format = Format(data[4])
+		out.Value = append([]byte{}, data[5:]...)
+
+		if out.Format == FormatLegacy {
+			return EngineID{}, errors.New("invalid input (reserved format)")
+		}
+	}
+
+	if err := checkValue(out.Format, out.Value); err != nil {
+		return EngineID{}, err
+	}
+
+	return out, nil
+}
+
+// Parse decodes engine ID given in hex as printed by SNMP tools, e.g. "0x80001f888059dc486145a26322" or "80:00:00:09:03:00:1a:2b:3c:4d:5e". Case is ignored.
+func Parse(name string) (EngineID, error) {
+	name = strings.ToLower(name)
+	if match, _ := regexp.MatchString(EngineIDRegEx, name); !match {
+		return EngineID{}, errors.New("invalid input (engine id)")
+	}
+
+	data, _ := hex.DecodeString(strings.ReplaceAll(strings.TrimPrefix(name, "0x"), ":", ""))
+
+	return Decode(data)
+}
+
+// Bytes encodes engine ID into binary form. If fields are not valid, e.g. enterprise number does not fit in 31 bits, an error is returned.
+func (e EngineID) Bytes() ([]byte, error) {
+	if e.Enterprise > 0x7fffffff {
+		return nil, errors.New("invalid input (enterprise)")
+	}
+
+	if err := checkValue(e.Format, e.Value); err != nil {
+		return nil, err
+	}
+
+	out := binary.BigEndian.AppendUint32(nil, e.Enterprise)
+	if e.Format != FormatLegacy {
+		out[0] |= 0x80
+		out = append(out, byte(e.Format))
+	}
+
+	return append(out, e.Value...), nil
+}
+
+// String returns engine ID as lower case hex digits, or empty string if fields are not valid.
+func (e EngineID) String() string {
+	data, err := e.Bytes()
+	if err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(data)
+}
+
+// ValueString returns value in text form according to format: IPv4 and IPv6 addresses in their usual forms, MAC address with ":" separated hex digits, text as is and other formats as hex digits.
+func (e EngineID) ValueString() string {
+	switch e.Format {
+	case FormatIPv4, FormatIPv6:
+		return net.IP(e.Value).String()
+	case FormatMAC:
+		return net.HardwareAddr(e.Value).String()
+	case FormatText:
+		return string(e.Value)
+	default:
+		return hex.EncodeToString(e.Value)
+	}
+}
+
+func isValidIdentifier(name string) bool {
+	match, _ := regexp.MatchString(rfc9039.DevUrnReservedRegEx, name)
+
+	return match
+}
+
+// ToUrnDev maps engine ID with MAC format into urn:dev:mac identifier, converting EUI-48 into EUI-64 by inserting "fffe", and other formats into urn:dev:org identifier with enterprise number and format name followed by value, for example "urn:dev:org:8072-ipv4:192.0.2.1" or "urn:dev:org:8072-enterprise128:59dc486145a26322". IPv4 addresses are shown in dotted form, text as is when it is valid urn:dev identifier and otherwise as hex after an "x" marker identifier, and other values as hex digits.
+func (e EngineID) ToUrnDev() (rfc9039.UrnDev, error) {
+	if _, err := e.Bytes(); err != nil {
+		return rfc9039.UrnDev{}, err
+	}
+
+	if e.Format == FormatMAC {
+		return rfc9039.Parse(rfc9039.UrnDevPrefix + "mac:" + hex.EncodeToString(e.Value[:3]) + "fffe" + hex.EncodeToString(e.Value[3:]))
+	}
+
+	value := hex.EncodeToString(e.Value)
+	switch {
+	case e.Format == FormatIPv4:
+		value = net.IP(e.Value).String()
+	case e.Format == FormatText && isValidIdentifier(string(e.Value)):
+		value = string(e.Value)
+	case e.Format == FormatText:
+		value = "x:" + value
+	}
+
+	return rfc9039.Parse(fmt.Sprintf("%sorg:%d-%s:%s", rfc9039.UrnDevPrefix, e.Enterprise, e.Format, value))
+}
+
+// FromUrnDev builds engine ID from urn:dev identifier. urn:dev:org identifiers created by ToUrnDev are converted back as is, and urn:dev:mac identifiers with EUI-64 created from EUI-48 are converted into MAC format engine ID of given enterprise. Other identifiers give an error.
+func FromUrnDev(devUrn rfc9039.UrnDev, enterprise uint32) (EngineID, error) {
+	switch devUrn.Subtype {
+	case "mac":
+		eui64 := devUrn.Eui64Identifier
+		if len(eui64) != 16 || eui64[6:10] != "fffe" || len(devUrn.Identifier) != 0 {
+			return EngineID{}, errors.New("invalid input (not eui-48 based)")
+		}
+		value, _ := hex.DecodeString(eui64[:6] + eui64[10:])
+		out := EngineID{Enterprise: enterprise, Format: FormatMAC, Value: value}
+
+		if _, err := out.Bytes(); err != nil {
+			return EngineID{}, err
+		}
+
+		return out, nil
+
+	case "org":
+		return fromOrg(devUrn)
+
+	default:
+		return EngineID{}, errors.New("invalid input (subtype)")
+	}
+}
+
+// parseFormat parses format name returned by Format.String.
+func parseFormat(name string) (Format, bool) {
+	if strings.HasPrefix(name, "enterprise") {
+		value, err := strconv.ParseUint(name[len("enterprise"):], 10, 8)
+		if err != nil || Format(value) < FormatEnterprise || Format(value).String() != name {
+			return 0, false
+		}
+		return Format(value), true
+	}
+
+	for _, format := range []Format{FormatLegacy, FormatIPv4, FormatIPv6, FormatMAC, FormatText, FormatOctets} {
+		if format.String() == name {
+			return format, true
+		}
+	}
+
+	return 0, false
+}
+
+func fromOrg(devUrn rfc9039.UrnDev) (EngineID, error) {
+	enterprise, err := strconv.ParseUint(devUrn.Organization, 10, 31)
+	if err != nil || len(devUrn.Identifier) < 2 {
+		return EngineID{}, errors.New("invalid input (org)")
+	}
+
+	out := EngineID{Enterprise: uint32(enterprise)}
+	name := devUrn.Identifier[0]
+	values := devUrn.Identifier[1:]
+
+	switch {
+	case name == "ipv4" && len(values) == 1:
+		ip := net.ParseIP(values[0]).To4()
+		if ip == nil {
+			return EngineID{}, errors.New("invalid input (ipv4)")
+		}
+		out.Format = FormatIPv4
+		out.Value = ip
+
+	case name == "text" && len(values) == 1:
+		out.Format = FormatText
+		out.Value = []byte(values[0])
+
+	case name == "text" && len(values) == 2 && values[0] == "x":
+		out.Format = FormatText
+		out.Value, err = hex.DecodeString(values[1])
+
+	default:
+		format, ok := parseFormat(name)
+		if !ok || len(values) != 1 {
+			return EngineID{}, errors.New("invalid input (format)")
+		}
+		out.Format = format
+		out.Value, err = hex.DecodeString(values[0])
+	}
+
+	if err != nil {
+		return EngineID{}, errors.New("invalid input (value)")
+	}
+
+	if _, err := out.Bytes(); err != nil {
+		return EngineID{}, err
+	}
+
+	return out, nil
+}
@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package snmp
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+func ExampleParse() {
+	engineID, _ := Parse("0x8000000903001A2B3C4D5E")
+	fmt.Println(engineID.Enterprise, engineID.Format, engineID.ValueString())
+
+	devUrn, _ := engineID.ToUrnDev()
+	fmt.Println(devUrn.FullName)
+	// Output: 9 mac 00:1a:2b:3c:4d:5e
+	// urn:dev:mac:001a2bfffe3c4d5e
+}
+
+func ExampleEngineID_Bytes() {
+	engineID := EngineID{Enterprise: 32473, Format: FormatText, Value: []byte("simulator-7")}
+	fmt.Println(engineID)
+
+	devUrn, _ := engineID.ToUrnDev()
+	fmt.Println(devUrn.FullName)
+	// Output: 80007ed90473696d756c61746f722d37
+	// urn:dev:org:32473-text:simulator-7
+}
+
+func TestParse(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		engine EngineID
+		value  string
+		urn    string
+	}{
+		{"80001f888059dc486145a26322", EngineID{Enterprise: 8072, Format: 128, Value: []byte{0x59, 0xdc, 0x48, 0x61, 0x45, 0xa2, 0x63, 0x22}}, "59dc486145a26322", "urn:dev:org:8072-enterprise128:59dc486145a26322"},
+		{"80:00:1f:88:01:c0:00:02:01", EngineID{Enterprise: 8072, Format: FormatIPv4, Value: []byte{192, 0, 2, 1}}, "192.0.2.1", "urn:dev:org:8072-ipv4:192.0.2.1"},
+		{"80001f880220010db8000000000000000000000001", EngineID{Enterprise: 8072, Format: FormatIPv6, Value: []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}}, "2001:db8::1", "urn:dev:org:8072-ipv6:20010db8000000000000000000000001"},
+		{"80007ed9046d7920656e67696e65", EngineID{Enterprise: 32473, Format: FormatText, Value: []byte("my engine")}, "my engine", "urn:dev:org:32473-text:x:6d7920656e67696e65"},
+		{"80007ed905deadbeef", EngineID{Enterprise: 32473, Format: FormatOctets, Value: []byte{0xde, 0xad, 0xbe, 0xef}}, "deadbeef", "urn:dev:org:32473-octets:deadbeef"},
+		{"000000090102030405060708", EngineID{Enterprise: 9, Format: FormatLegacy, Value: []byte{1, 2, 3, 4, 5, 6, 7, 8}}, "0102030405060708", "urn:dev:org:9-legacy:0102030405060708"},
+	} {
+		engineID, err := Parse(test.name)
+		if err != nil {
+			t.Fatalf("Failed to parse %s", test.name)
+			return
+		}
+		assert.Equal(t, test.engine, engineID, test.name)
+		assert.Equal(t, test.value, engineID.ValueString(), test.name)
+
+		devUrn, err := engineID.ToUrnDev()
+		if err != nil {
+			t.Fatalf("Failed to map %s", test.name)
+			return
+		}
+		assert.Equal(t, test.urn, devUrn.FullName, test.name)
+
+		// Encoding the other way gives the same engine ID
+		back, err := FromUrnDev(devUrn, 0)
+		if err != nil {
+			t.Fatalf("Failed to convert back %s", devUrn.FullName)
+			return
+		}
+		assert.Equal(t, test.engine, back, test.name)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, input := range []string{"", "0x", "80001f88", "80001f88010a0000", "80001f880220010db8", "80001f8803001a2b3c4d", "80001f8804", "80001f8806ff", "80001f887f00", "80000009c8", "0000000901020304050607", "80000009000102030405060708", "80001f888059dc486145a26322xx", "80001f88800102030405060708090a0b0c0d0e0f101112131415161718191a1b1c"} {
+		_, err := Parse(input)
+		assert.NotNil(t, err, input)
+	}
+}
+
+func TestBytes(t *testing.T) {
+	data, err := EngineID{Enterprise: 8072, Format: FormatMAC, Value: []byte{0x00, 0x24, 0xbe, 0x80, 0x4f, 0xf1}}.Bytes()
+	if err != nil {
+		t.Fatalf("Failed to encode")
+		return
+	}
+	assert.Equal(t, []byte{0x80, 0x00, 0x1f, 0x88, 0x03, 0x00, 0x24, 0xbe, 0x80, 0x4f, 0xf1}, data)
+
+	for _, input := range []EngineID{
+		{Enterprise: 0x80000000, Format: FormatOctets, Value: []byte{1}},
+		{Enterprise: 8072, Format: 6, Value: []byte{1}},
+		{Enterprise: 8072, Format: FormatIPv4, Value: []byte{1, 2, 3}},
+		{Enterprise: 8072, Format: FormatLegacy, Value: []byte{1}},
+		{Enterprise: 9, Format: 200},
+		{Enterprise: 9, Format: FormatEnterprise, Value: []byte{}},
+	} {
+		_, err := input.Bytes()
+		assert.NotNil(t, err, input.Format.String())
+		assert.Equal(t, "", input.String())
+	}
+}
+
+func TestToUrnDevEnterpriseFormat(t *testing.T) {
+	// Shortest valid engine ID with enterprise specific format maps into urn:dev and back
+	engine, err := Parse("80000009c801")
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+
+	devUrn, err := engine.ToUrnDev()
+	if err != nil {
+		t.Fatalf("Failed to map: %v", err)
+		return
+	}
+	assert.Equal(t, "urn:dev:org:9-enterprise200:01", devUrn.FullName)
+
+	back, err := FromUrnDev(devUrn, 0)
+	if err != nil {
+		t.Fatalf("Failed to convert back %s", devUrn.FullName)
+		return
+	}
+	assert.Equal(t, engine, back)
+
+	_, err = EngineID{Enterprise: 9, Format: 200}.ToUrnDev()
+	assert.NotNil(t, err)
+}
+
+func TestFromUrnDev(t *testing.T) {
+	devUrn, _ := rfc9039.Parse("urn:dev:mac:0024befffe804ff1")
+	engineID, err := FromUrnDev(devUrn, 32473)
+	if err != nil {
+		t.Fatalf("Failed to convert")
+		return
+	}
+	assert.Equal(t, "80007ed9030024be804ff1", engineID.String())
+
+	for _, name := range []string{"urn:dev:mac:0024be0000804ff1", "urn:dev:ow:10e2073a01080063", "urn:dev:org:8072-reserved:00", "urn:dev:org:8072-enterprise12:00", "urn:dev:org:8072-ipv4:192.0.2", "urn:dev:org:8072-octets:0", "urn:dev:org:8072-text", "urn:dev:org:4294967295-octets:00"} {
+		devUrn, err := rfc9039.Parse(name)
+		if err != nil {
+			t.Fatalf("Failed to parse %s", name)
+			return
+		}
+		_, err = FromUrnDev(devUrn, 32473)
+		assert.NotNil(t, err, name)
+	}
+}
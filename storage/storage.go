@@ -0,0 +1,283 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package storage provides tools for parsing storage device identifiers: NAA World Wide Names, NVMe EUI-64 and NGUID and SCSI VPD page 0x83 designators.
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+const NameRegEx = "^(naa\\.|0x|eui\\.)([0-9a-f]+)$"
+
+// VPDPageDeviceIdentification is the page code of SCSI Device Identification VPD page.
+const VPDPageDeviceIdentification = 0x83
+
+// NAA formats.
+const (
+	NAAIEEEExtended           = 2
+	NAALocallyAssigned        = 3
+	NAAIEEERegistered         = 5
+	NAAIEEERegisteredExtended = 6
+)
+
+// SCSI designator code sets.
+const (
+	CodeSetBinary = 1
+	CodeSetASCII  = 2
+	CodeSetUTF8   = 3
+)
+
+// SCSI designator types.
+const (
+	DesignatorVendorSpecific = 0x0
+	DesignatorT10VendorID    = 0x1
+	DesignatorEUI64          = 0x2
+	DesignatorNAA            = 0x3
+	DesignatorSCSIName       = 0x8
+)
+
+// SCSI designator associations.
+const (
+	AssociationLogicalUnit  = 0
+	AssociationTargetPort   = 1
+	AssociationTargetDevice = 2
+)
+
+// Kind defines the format of an identifier.
+type Kind int
+
+const (
+	NAA Kind = iota
+	EUI64
+	NGUID
+)
+
+// String returns name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case NAA:
+		return "naa"
+	case EUI64:
+		return "eui64"
+	case NGUID:
+		return "nguid"
+	default:
+		return "unknown"
+	}
+}
+
+// Identifier captures binary storage device identifier.
+type Identifier struct {
+	// Kind is the format of the identifier.
+	Kind Kind
+	// Value is the identifier in big endian order, 8 bytes for NAA 2, 3 and 5 and EUI-64 and 16 bytes for NAA 6 and NGUID.
+	Value []byte
+}
+
+// Designator captures single designation descriptor of SCSI Device Identification VPD page.
+type Designator struct {
+	// ProtocolIdentifier is the transport protocol, valid when PIV is set.
+	ProtocolIdentifier uint8
+	// CodeSet is the encoding of Value, e.g. CodeSetBinary.
+	CodeSet uint8
+	// PIV is set when ProtocolIdentifier is valid.
+	PIV bool
+	// Association tells what the designator identifies, e.g. AssociationLogicalUnit.
+	Association uint8
+	// Type is the designator type, e.g. DesignatorNAA.
+	Type uint8
+	// Value is the designator.
+	Value []byte
+}
+
+// ParseNAA parses NAA World Wide Name in binary form. Formats 2, 3 and 5 are 8 bytes and format 6 is 16 bytes. If incorrectly formed or unsupported identifier is given as input an error is returned.
+func ParseNAA(data []byte) (Identifier, error) {
+	if len(data) == 0 {
+		return Identifier{}, errors.New("invalid input (naa)")
+	}
+
+	switch data[0] >> 4 {
+	case NAAIEEEExtended, NAALocallyAssigned, NAAIEEERegistered:
+		if len(data) != 8 {
+			return Identifier{}, errors.New("invalid input (naa length)")
+		}
+	case NAAIEEERegisteredExtended:
+		if len(data) != 16 {
+			return Identifier{}, errors.New("invalid input (naa length)")
+		}
+	default:
+		return Identifier{}, errors.New("invalid input (naa format)")
+	}
+
+	return Identifier{Kind: NAA, Value: append([]byte{}, data...)}, nil
+}
+
+// ParseEUI64 parses NVMe namespace EUI-64. All zero value, reported when EUI-64 is not supported, gives an error.
+func ParseEUI64(data []byte) (Identifier, error) {
+	if len(data) != 8 || bytes.Equal(data, make([]byte, 8)) {
+		return Identifier{}, errors.New("invalid input (eui-64)")
+	}
+
+	return Identifier{Kind: EUI64, Value: append([]byte{}, data...)}, nil
+}
+
+// ParseNGUID parses NVMe namespace globally unique identifier. All zero value, reported when NGUID is not supported, gives an error.
+func ParseNGUID(data []byte) (Identifier, error) {
+	if len(data) != 16 || bytes.Equal(data, make([]byte, 16)) {
+		return Identifier{}, errors.New("invalid input (nguid)")
+	}
+
+	return Identifier{Kind: NGUID, Value: append([]byte{}, data...)}, nil
+}
+
+// ParseName parses identifier in text form used by Linux and udev: "naa.5000c500a1b2c3d4" or "0x5000c500a1b2c3d4" for NAA and "eui.0025385b71b07e2f" for EUI-64 or with 32 hex digits for NGUID. Case is ignored.
+func ParseName(name string) (Identifier, error) {
+	match := regexp.MustCompile(NameRegEx).FindStringSubmatch(strings.ToLower(name))
+	if match == nil || len(match[2])%2 != 0 {
+		return Identifier{}, errors.New("invalid input (name)")
+	}
+
+	value, _ := hex.DecodeString(match[2])
+
+	if match[1] != "eui." {
+		return ParseNAA(value)
+	}
+
+	if len(value) == 16 {
+		return ParseNGUID(value)
+	}
+
+	return ParseEUI64(value)
+}
+
+// ParseVPD83 parses designation descriptors of SCSI Device Identification VPD page 0x83 including the 4 byte page header, e.g. as read from /sys/block/sda/device/vpd_pg83. If incorrectly formed page is given as input an error is returned.
+func ParseVPD83(data []byte) ([]Designator, error) {
+	if len(data) < 4 || data[1] != VPDPageDeviceIdentification {
+		return nil, errors.New("invalid input (vpd page)")
+	}
+
+	length := int(binary.BigEndian.Uint16(data[2:]))
+	if len(data) < 4+length {
+		return nil, errors.New("invalid input (vpd page length)")
+	}
+
+	out := []Designator{}
+	page := data[4 : 4+length]
+
+	for offset := 0; offset < len(page); {
+		if offset+4 > len(page) || offset+4+int(page[offset+3]) > len(page) {
+			return nil, errors.New("invalid input (designator length)")
+		}
+
+		out = append(out, Designator{
+			ProtocolIdentifier: page[offset] >> 4,
+			CodeSet:            page[offset] & 0x0f,
+			PIV:                page[offset+1]&0x80 != 0,
+			Association:        (page[offset+1] >> 4) & 0x03,
+			Type:               page[offset+1] & 0x0f,
+			Value:              append([]byte{}, page[offset+4:offset+4+int(page[offset+3])]...),
+		})
+
+		offset += 4 + int(page[offset+3])
+	}
+
+	return out, nil
+}
+
+// Identifier converts NAA and 8 byte EUI-64 designators into Identifier. Other designators give an error.
+func (d Designator) Identifier() (Identifier, error) {
+	if d.CodeSet != CodeSetBinary {
+		return Identifier{}, errors.New("invalid input (code set)")
+	}
+
+	switch d.Type {
+	case DesignatorNAA:
+		return ParseNAA(d.Value)
+	case DesignatorEUI64:
+		return ParseEUI64(d.Value)
+	default:
+		return Identifier{}, errors.New("invalid input (designator type)")
+	}
+}
+
+// LogicalUnitIdentifier returns the first NAA or EUI-64 designator of the logical unit, preferring NAA, which is what Linux uses as disk WWN.
+func LogicalUnitIdentifier(designators []Designator) (Identifier, error) {
+	for _, designatorType := range []uint8{DesignatorNAA, DesignatorEUI64} {
+		for _, designator := range designators {
+			if designator.Association != AssociationLogicalUnit || designator.Type != designatorType {
+				continue
+			}
+
+			if out, err := designator.Identifier(); err == nil {
+				return out, nil
+			}
+		}
+	}
+
+	return Identifier{}, errors.New("invalid input (no logical unit identifier)")
+}
+
+// NAAFormat returns NAA format of NAA identifier and 0 for other kinds.
+func (i Identifier) NAAFormat() int {
+	if i.Kind != NAA || len(i.Value) == 0 {
+		return 0
+	}
+
+	return int(i.Value[0] >> 4)
+}
+
+// OUI returns IEEE company identifier embedded in the identifier as 6 lower case hex digits. NAA 3 is locally assigned and has none.
+func (i Identifier) OUI() (string, bool) {
+	var oui uint32
+
+	switch {
+	case i.Kind == EUI64 && len(i.Value) == 8:
+		oui = uint32(i.Value[0])<<16 | uint32(i.Value[1])<<8 | uint32(i.Value[2])
+	case i.Kind == NGUID && len(i.Value) == 16:
+		oui = uint32(i.Value[8])<<16 | uint32(i.Value[9])<<8 | uint32(i.Value[10])
+	case i.NAAFormat() == NAAIEEEExtended:
+		oui = uint32(i.Value[2])<<16 | uint32(i.Value[3])<<8 | uint32(i.Value[4])
+	case i.NAAFormat() == NAAIEEERegistered || i.NAAFormat() == NAAIEEERegisteredExtended:
+		oui = uint32(i.Value[0]&0x0f)<<20 | uint32(i.Value[1])<<12 | uint32(i.Value[2])<<4 | uint32(i.Value[3])>>4
+	default:
+		return "", false
+	}
+
+	return fmt.Sprintf("%06x", oui), true
+}
+
+// String returns identifier in text form used by Linux, "naa." or "eui." followed by lower case hex digits.
+func (i Identifier) String() string {
+	if i.Kind == NAA {
+		return "naa." + hex.EncodeToString(i.Value)
+	}
+
+	return "eui." + hex.EncodeToString(i.Value)
+}
+
+// ToUrnDev maps EUI-64 into urn:dev:mac identifier and NAA and NGUID identifiers into urn:dev:org identifier with Private Enterprise Number looked up by OUI from penByOUI, with keys as 6 lower case hex digits, for example "urn:dev:org:32473-naa.5000c500a1b2c3d4". If OUI is not configured, or identifier has no OUI, an error is returned.
+func (i Identifier) ToUrnDev(penByOUI map[string]int) (rfc9039.UrnDev, error) {
+	if i.Kind == EUI64 {
+		return rfc9039.Parse(rfc9039.UrnDevPrefix + "mac:" + hex.EncodeToString(i.Value))
+	}
+
+	oui, ok := i.OUI()
+	if !ok {
+		return rfc9039.UrnDev{}, errors.New("invalid input (no oui)")
+	}
+
+	pen, ok := penByOUI[oui]
+	if !ok || pen <= 0 {
+		return rfc9039.UrnDev{}, errors.New("invalid input (unknown oui)")
+	}
+
+	return rfc9039.Parse(fmt.Sprintf("%sorg:%d-%s", rfc9039.UrnDevPrefix, pen, i))
+}
@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package storage
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var testPENs = map[string]int{"000c50": 32473, "002538": 32473, "00a0b8": 32473}
+
+func decodeHex(text string) []byte {
+	data, _ := hex.DecodeString(text)
+
+	return data
+}
+
+// vpdPage builds VPD page 0x83 of designators given as hex of their first two bytes and the designator value.
+func vpdPage(designators ...string) []byte {
+	page := []byte{}
+	for index := 0; index < len(designators); index += 2 {
+		value := decodeHex(designators[index+1])
+		page = append(page, decodeHex(designators[index])...)
+		page = append(page, 0, byte(len(value)))
+		page = append(page, value...)
+	}
+
+	return append([]byte{0x00, VPDPageDeviceIdentification, byte(len(page) >> 8), byte(len(page))}, page...)
+}
+
+func ExampleParseVPD83() {
+	page := vpdPage(
+		"0201", hex.EncodeToString([]byte("ATA     ST4000NM000A-2HZ100                 ZC12")),
+		"0103", "5000c500a1b2c3d4",
+		"6194", "00000001")
+
+	designators, _ := ParseVPD83(page)
+	id, _ := LogicalUnitIdentifier(designators)
+	fmt.Println(len(designators), id)
+
+	devUrn, _ := id.ToUrnDev(map[string]int{"000c50": 32473})
+	fmt.Println(devUrn.FullName)
+	// Output: 3 naa.5000c500a1b2c3d4
+	// urn:dev:org:32473-naa.5000c500a1b2c3d4
+}
+
+func ExampleParseEUI64() {
+	id, _ := ParseEUI64([]byte{0x00, 0x25, 0x38, 0x5b, 0x71, 0xb0, 0x7e, 0x2f})
+	devUrn, _ := id.ToUrnDev(nil)
+	fmt.Println(id, devUrn.FullName)
+	// Output: eui.0025385b71b07e2f urn:dev:mac:0025385b71b07e2f
+}
+
+func TestParseName(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		kind   Kind
+		format int
+		oui    string
+		urn    string
+	}{
+		{"naa.5000C500A1B2C3D4", NAA, 5, "000c50", "urn:dev:org:32473-naa.5000c500a1b2c3d4"},
+		{"0x5000c500a1b2c3d4", NAA, 5, "000c50", "urn:dev:org:32473-naa.5000c500a1b2c3d4"},
+		{"naa.600a0b800012345600001234abcd5678", NAA, 6, "00a0b8", "urn:dev:org:32473-naa.600a0b800012345600001234abcd5678"},
+		{"naa.20000024be804ff1", NAA, 2, "0024be", ""},
+		{"naa.3000000000000001", NAA, 3, "", ""},
+		{"eui.0025385b71b07e2f", EUI64, 0, "002538", "urn:dev:mac:0025385b71b07e2f"},
+		{"eui.00000000000000010025380000000001", NGUID, 0, "002538", "urn:dev:org:32473-eui.00000000000000010025380000000001"},
+	} {
+		id, err := ParseName(test.name)
+		if err != nil {
+			t.Fatalf("Failed to parse %s", test.name)
+			return
+		}
+		assert.Equal(t, test.kind, id.Kind, test.name)
+		assert.Equal(t, test.format, id.NAAFormat(), test.name)
+
+		oui, ok := id.OUI()
+		assert.Equal(t, test.oui, oui, test.name)
+		assert.Equal(t, test.oui != "", ok, test.name)
+
+		devUrn, err := id.ToUrnDev(testPENs)
+		assert.Equal(t, test.urn, devUrn.FullName, test.name)
+		assert.Equal(t, test.urn == "", err != nil, test.name)
+	}
+}
+
+func TestParseNameInvalid(t *testing.T) {
+	for _, input := range []string{"", "naa.", "naa.5000c500a1b2c3d", "naa.5000c500a1b2c3d4aa", "naa.6000c500a1b2c3d4", "naa.1000c500a1b2c3d4", "eui.0000000000000000", "eui.0025385b71b07e", "wwn-0x5000c500a1b2c3d4", "t10.ATA"} {
+		_, err := ParseName(input)
+		assert.NotNil(t, err, input)
+	}
+
+	for _, input := range [][]byte{nil, make([]byte, 16), decodeHex("0025385b71b07e2f")} {
+		_, err := ParseNGUID(input)
+		assert.NotNil(t, err, fmt.Sprintf("%x", input))
+	}
+}
+
+func TestParseVPD83(t *testing.T) {
+	page := vpdPage(
+		"6193", "500a0b8000123456",
+		"0102", "0025385b71b07e2f",
+		"0103", "600a0b800012345600001234abcd5678")
+
+	designators, err := ParseVPD83(page)
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, Designator{ProtocolIdentifier: 6, CodeSet: CodeSetBinary, PIV: true, Association: AssociationTargetPort, Type: DesignatorNAA, Value: decodeHex("500a0b8000123456")}, designators[0])
+	assert.Equal(t, 3, len(designators))
+
+	// NAA of the logical unit is preferred over EUI-64 and target port NAA
+	id, err := LogicalUnitIdentifier(designators)
+	if err != nil {
+		t.Fatalf("Failed to find logical unit identifier")
+		return
+	}
+	assert.Equal(t, "naa.600a0b800012345600001234abcd5678", id.String())
+
+	id, _ = LogicalUnitIdentifier(designators[:2])
+	assert.Equal(t, "eui.0025385b71b07e2f", id.String())
+
+	_, err = LogicalUnitIdentifier(designators[:1])
+	assert.NotNil(t, err)
+}
+
+func TestParseVPD83Invalid(t *testing.T) {
+	for _, input := range []string{"", "0080000c", "00830010" + "01030008", "0083000c" + "01030009" + "5000c500a1b2c3d4", "00830002" + "0103"} {
+		_, err := ParseVPD83(decodeHex(input))
+		assert.NotNil(t, err, input)
+	}
+
+	_, err := Designator{CodeSet: CodeSetASCII, Type: DesignatorNAA, Value: []byte("5000c500a1b2c3d4")}.Identifier()
+	assert.NotNil(t, err)
+	assert.Equal(t, "nguid", NGUID.String())
+}
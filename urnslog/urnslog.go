@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package urnslog provides log/slog handler redacting urn:dev device identifiers.
+package urnslog
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+// Options configures Handler.
+type Options struct {
+	// Policy selects how urn:dev values are redacted.
+	Policy rfc9039.RedactionPolicy
+	// HashKey is used as HMAC-SHA256 key with rfc9039.RedactHash and is required by it. When not set identifiers are masked as with rfc9039.RedactMaskSerial and no hash is logged.
+	HashKey []byte
+}
+
+// Handler wraps slog.Handler and redacts urn:dev values before passing records on. rfc9039.UrnDev attribute values are logged as groups like rfc9039.UrnDev.LogValue but redacted according to Policy, and valid urn:dev names found in string attributes and in the message are replaced with their redacted form.
+type Handler struct {
+	next slog.Handler
+	opts Options
+}
+
+// NewHandler creates Handler passing redacted records to next.
+func NewHandler(next slog.Handler, opts Options) *Handler {
+	return &Handler{next: next, opts: opts}
+}
+
+// Enabled reports whether next handler handles records at given level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle redacts record and passes it to next handler.
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	out := slog.NewRecord(record.Time, record.Level, h.redactString(record.Message), record.PC)
+
+	record.Attrs(func(attr slog.Attr) bool {
+		out.AddAttrs(h.redactAttr(attr))
+		return true
+	})
+
+	return h.next.Handle(ctx, out)
+}
+
+// WithAttrs returns Handler whose next handler has redacted attrs.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		redacted = append(redacted, h.redactAttr(attr))
+	}
+
+	return &Handler{next: h.next.WithAttrs(redacted), opts: h.opts}
+}
+
+// WithGroup returns Handler whose next handler opens group name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name), opts: h.opts}
+}
+
+// redactString replaces valid urn:dev names found in text with rfc9039.FindAll with their redacted form.
+func (h *Handler) redactString(text string) string {
+	if h.opts.Policy == rfc9039.RedactFull || !strings.Contains(strings.ToLower(text), "urn") {
+		return text
+	}
+
+	var out strings.Builder
+	last := 0
+
+	for _, match := range rfc9039.FindAll([]byte(text)) {
+		out.WriteString(text[last:match.Start])
+		out.WriteString(match.UrnDev.Redact(h.opts.Policy, h.opts.HashKey))
+		last = match.End
+	}
+	out.WriteString(text[last:])
+
+	return out.String()
+}
+
+func (h *Handler) redactAttr(attr slog.Attr) slog.Attr {
+	value := attr.Value
+
+	if value.Kind() == slog.KindLogValuer {
+		switch devUrn := value.Any().(type) {
+		case rfc9039.UrnDev:
+			return slog.Attr{Key: attr.Key, Value: devUrn.RedactedLogValue(h.opts.Policy, h.opts.HashKey)}
+		case *rfc9039.UrnDev:
+			if devUrn != nil {
+				return slog.Attr{Key: attr.Key, Value: devUrn.RedactedLogValue(h.opts.Policy, h.opts.HashKey)}
+			}
+		}
+
+		value = value.Resolve()
+	}
+
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, h.redactString(value.String()))
+
+	case slog.KindGroup:
+		attrs := []slog.Attr{}
+		for _, member := range value.Group() {
+			attrs = append(attrs, h.redactAttr(member))
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(attrs...)}
+
+	default:
+		return slog.Attr{Key: attr.Key, Value: value}
+	}
+}
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package urnslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+func removeTime(groups []string, attr slog.Attr) slog.Attr {
+	if attr.Key == slog.TimeKey && len(groups) == 0 {
+		return slog.Attr{}
+	}
+
+	return attr
+}
+
+func ExampleNewHandler() {
+	next := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{ReplaceAttr: removeTime})
+	logger := slog.New(NewHandler(next, Options{Policy: rfc9039.RedactMaskSerial}))
+
+	devUrn, _ := rfc9039.Parse("urn:dev:ops:32473-Refrigerator-5002")
+	logger.Info("door opened", "device", devUrn, "detail", "seen urn:dev:mac:0024befffe804ff1_eth0.")
+	// Output: level=INFO msg="door opened" device.subtype=ops device.organization=32473 device.product=Refrigerator device.id=urn:dev:ops:32473-Refrigerator-**** detail="seen urn:dev:mac:****************_eth0."
+}
+
+func logJSON(t *testing.T, opts Options, log func(logger *slog.Logger)) map[string]any {
+	var buffer bytes.Buffer
+	log(slog.New(NewHandler(slog.NewJSONHandler(&buffer, nil), opts)))
+
+	out := map[string]any{}
+	if err := json.Unmarshal(buffer.Bytes(), &out); err != nil {
+		t.Fatalf("Failed to decode log output")
+	}
+
+	return out
+}
+
+func TestHandlerPolicies(t *testing.T) {
+	devUrn, _ := rfc9039.Parse("urn:dev:mac:0024befffe804ff1_eth0")
+
+	for _, test := range []struct {
+		policy rfc9039.RedactionPolicy
+		id     string
+	}{
+		{rfc9039.RedactFull, "urn:dev:mac:0024befffe804ff1_eth0"},
+		{rfc9039.RedactMaskSerial, "urn:dev:mac:****************_eth0"},
+		{rfc9039.RedactMaskEUITail, "urn:dev:mac:0024be**********_eth0"},
+	} {
+		out := logJSON(t, Options{Policy: test.policy}, func(logger *slog.Logger) {
+			logger.Info("message", "device", devUrn, "text", "at "+devUrn.FullName)
+		})
+		assert.Equal(t, map[string]any{"subtype": "mac", "id": test.id}, out["device"], test.policy.String())
+		assert.Equal(t, "at "+test.id, out["text"], test.policy.String())
+	}
+
+	out := logJSON(t, Options{Policy: rfc9039.RedactHash, HashKey: []byte("secret")}, func(logger *slog.Logger) {
+		logger.Info("message", "device", &devUrn)
+	})
+	id := out["device"].(map[string]any)["id"].(string)
+	assert.Regexp(t, "^urn:dev:mac:#[0-9a-f]{16}_eth0$", id)
+
+	other := logJSON(t, Options{Policy: rfc9039.RedactHash, HashKey: []byte("other secret")}, func(logger *slog.Logger) {
+		logger.Info("message", "device", &devUrn)
+	})
+	assert.NotEqual(t, id, other["device"].(map[string]any)["id"])
+
+	// No hash is logged without a key
+	unkeyed := logJSON(t, Options{Policy: rfc9039.RedactHash}, func(logger *slog.Logger) {
+		logger.Info("message", "device", &devUrn)
+	})
+	assert.Equal(t, "urn:dev:mac:****************_eth0", unkeyed["device"].(map[string]any)["id"])
+}
+
+func TestHandlerNested(t *testing.T) {
+	out := logJSON(t, Options{Policy: rfc9039.RedactMaskSerial}, func(logger *slog.Logger) {
+		logger = logger.With("gateway", "urn:dev:os:32473-5002").WithGroup("request")
+		logger.Info("routing urn:dev:ops:32473-Refrigerator-5002",
+			slog.Group("target", "name", "URN:DEV:ow:10e2073a01080063", "count", 2),
+			"invalid", "urn:dev:mac:0024befffe804ff",
+			"other", "urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6")
+	})
+
+	assert.Equal(t, "routing urn:dev:ops:32473-Refrigerator-****", out["msg"])
+	assert.Equal(t, "urn:dev:os:32473-****", out["gateway"])
+
+	request := out["request"].(map[string]any)
+	assert.Equal(t, map[string]any{"name": "urn:dev:ow:****************", "count": 2.0}, request["target"])
+	assert.Equal(t, "urn:dev:mac:0024befffe804ff", request["invalid"])
+	assert.Equal(t, "urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6", request["other"])
+}
+
+func TestLogValue(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buffer, nil))
+
+	devUrn, _ := rfc9039.Parse("urn:dev:mac:0024befffe804ff1")
+	logger.Info("message", "device", devUrn)
+
+	out := map[string]any{}
+	if err := json.Unmarshal(buffer.Bytes(), &out); err != nil {
+		t.Fatalf("Failed to decode log output")
+		return
+	}
+	assert.Equal(t, map[string]any{"subtype": "mac", "id": "urn:dev:mac:0024be**********"}, out["device"])
+}
@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package vidpid provides tools for parsing USB and PCI vendor and product identifiers and resolving their names from usb.ids and pci.ids databases.
+package vidpid
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/RisingEdgeSolutions/device-identifiers/rfc9039"
+)
+
+const IDRegEx = "^([0-9A-Fa-f]{4}):([0-9A-Fa-f]{4})$"
+const UsbModaliasRegEx = "^usb:v([0-9A-Fa-f]{4})p([0-9A-Fa-f]{4})"
+const PciModaliasRegEx = "^pci:v([0-9A-Fa-f]{8})d([0-9A-Fa-f]{8})"
+
+// Bus defines which bus vendor and product identifiers belong to.
+type Bus int
+
+const (
+	USB Bus = iota
+	PCI
+)
+
+// ID captures vendor and product identifier pair of a device.
+type ID struct {
+	// Bus is the bus the identifiers are assigned for.
+	Bus Bus
+	// Vendor is the vendor identifier (VID).
+	Vendor uint16
+	// Product is the product identifier (PID), called device identifier on PCI.
+	Product uint16
+}
+
+// String returns name of the bus as used in modalias strings and urn:dev subtype.
+func (b Bus) String() string {
+	switch b {
+	case USB:
+		return "usb"
+	case PCI:
+		return "pci"
+	default:
+		return "unknown"
+	}
+}
+
+func parseHex16(name string) uint16 {
+	value, _ := strconv.ParseUint(name, 16, 32)
+
+	return uint16(value)
+}
+
+// ParseID parses vendor and product identifiers given in "1d6b:0002" form as printed by lsusb and lspci -n. If incorrectly formed identifier is given as input an error is returned.
+func ParseID(bus Bus, name string) (ID, error) {
+	match := regexp.MustCompile(IDRegEx).FindStringSubmatch(name)
+	if match == nil {
+		return ID{}, errors.New("invalid input (id)")
+	}
+
+	return ID{Bus: bus, Vendor: parseHex16(match[1]), Product: parseHex16(match[2])}, nil
+}
+
+// ParseModalias parses vendor and product identifiers from sysfs modalias string such as "usb:v1D6Bp0002d0510dc09dsc00dp03ic09isc00ip00in00" or "pci:v00008086d00001237sv00000000sd00000000bc06sc00i00". If incorrectly formed modalias is given as input an error is returned.
+func ParseModalias(name string) (ID, error) {
+	if match := regexp.MustCompile(UsbModaliasRegEx).FindStringSubmatch(name); match != nil {
+		return ID{Bus: USB, Vendor: parseHex16(match[1]), Product: parseHex16(match[2])}, nil
+	}
+
+	if match := regexp.MustCompile(PciModaliasRegEx).FindStringSubmatch(name); match != nil {
+		// PCI identifiers are 16-bit but modalias uses 32-bit fields
+		if match[1][:4] != "0000" || match[2][:4] != "0000" {
+			return ID{}, errors.New("invalid input (modalias)")
+		}
+
+		return ID{Bus: PCI, Vendor: parseHex16(match[1][4:]), Product: parseHex16(match[2][4:])}, nil
+	}
+
+	return ID{}, errors.New("invalid input (modalias)")
+}
+
+// String returns identifiers in "1d6b:0002" form.
+func (id ID) String() string {
+	return fmt.Sprintf("%04x:%04x", id.Vendor, id.Product)
+}
+
+func isValidIdentifier(name string) bool {
+	match, _ := regexp.MatchString(rfc9039.DevUrnReservedRegEx, name)
+
+	return match
+}
+
+// ToUrnDev builds urn:dev otherbody identifier with bus as subtype, for example "urn:dev:usb:0403-6001:A50285BI". Hex digits are always lower case so the same device always maps to the same identifier. Surrounding white space is trimmed from serial and serial that is not valid urn:dev identifier is hex encoded after an "x" marker identifier, for example "urn:dev:usb:0403-6001:x:412042". If serial is empty the identifier only names the product model.
+func (id ID) ToUrnDev(serial string) (rfc9039.UrnDev, error) {
+	if id.Bus != USB && id.Bus != PCI {
+		return rfc9039.UrnDev{}, errors.New("invalid input (bus)")
+	}
+
+	name := fmt.Sprintf("%s%s:%04x-%04x", rfc9039.UrnDevPrefix, id.Bus, id.Vendor, id.Product)
+
+	serial = strings.TrimSpace(serial)
+	if serial != "" {
+		if isValidIdentifier(serial) {
+			name += ":" + serial
+		} else {
+			name += ":x:" + hex.EncodeToString([]byte(serial))
+		}
+	}
+
+	return rfc9039.Parse(name)
+}
+
+// Database captures vendor and product names from usb.ids or pci.ids file.
+type Database struct {
+	vendors map[uint16]*vendorEntry
+}
+
+type vendorEntry struct {
+	name     string
+	products map[uint16]string
+}
+
+var vendorLineRegEx = regexp.MustCompile("^([0-9a-f]{4})\\s+(.+)$")
+var productLineRegEx = regexp.MustCompile("^\\t([0-9a-f]{4})\\s+(.+)$")
+
+// ParseDatabase reads usb.ids or pci.ids formatted database. Only vendor and product lines are used, subsystem lines and other sections such as device classes are skipped.
+func ParseDatabase(r io.Reader) (*Database, error) {
+	out := &Database{vendors: map[uint16]*vendorEntry{}}
+
+	var current *vendorEntry
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if match := vendorLineRegEx.FindStringSubmatch(line); match != nil {
+			current = &vendorEntry{name: match[2], products: map[uint16]string{}}
+			out.vendors[parseHex16(match[1])] = current
+			continue
+		}
+
+		if match := productLineRegEx.FindStringSubmatch(line); match != nil {
+			if current != nil {
+				current.products[parseHex16(match[1])] = match[2]
+			}
+			continue
+		}
+
+		if !strings.HasPrefix(line, "\t") {
+			// Start of another section, e.g. "C 09  Hub" device class list
+			current = nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// OpenDatabase reads usb.ids or pci.ids formatted database from file, for example "/usr/share/hwdata/usb.ids".
+func OpenDatabase(path string) (*Database, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseDatabase(f)
+}
+
+// VendorName returns name of the vendor, or false if vendor is not in the database.
+func (d *Database) VendorName(id ID) (string, bool) {
+	vendor, ok := d.vendors[id.Vendor]
+	if !ok {
+		return "", false
+	}
+
+	return vendor.name, true
+}
+
+// ProductName returns name of the product, or false if product is not in the database.
+func (d *Database) ProductName(id ID) (string, bool) {
+	vendor, ok := d.vendors[id.Vendor]
+	if !ok {
+		return "", false
+	}
+
+	name, ok := vendor.products[id.Product]
+
+	return name, ok
+}
@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: BSD-3-Clause
+
+package vidpid
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testUsbIds = `#
+#	List of USB ID's
+#
+# Syntax:
+# vendor  vendor_name
+#	device  device_name				<-- single tab
+#		interface  interface_name		<-- two tabs
+
+0403  Future Technology Devices International, Ltd
+	6001  FT232 Serial (UART) IC
+	6010  FT2232C/D/H Dual UART/FIFO IC
+1d6b  Linux Foundation
+	0001  1.1 root hub
+	0002  2.0 root hub
+	0003  3.0 root hub
+
+# List of known device classes, subclasses and protocols
+
+C 09  Hub
+	00  Unused
+		00  Full speed (or root) hub
+`
+
+const testPciIds = `8086  Intel Corporation
+	1237  440FX - 82441FX PMC [Natoma]
+	100e  82540EM Gigabit Ethernet Controller
+		1028 002e  Optiplex GX260
+C 02  Network controller
+	00  Ethernet controller
+`
+
+func ExampleParseModalias() {
+	id, _ := ParseModalias("usb:v0403p6001d0600dc00dsc00dp00icFFiscFFipFFin00")
+	devUrn, _ := id.ToUrnDev("A50285BI")
+	fmt.Println(id)
+	fmt.Println(devUrn.FullName)
+	// Output: 0403:6001
+	// urn:dev:usb:0403-6001:A50285BI
+}
+
+func TestParseID(t *testing.T) {
+	value, err := ParseID(USB, "1D6B:0002")
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, ID{Bus: USB, Vendor: 0x1d6b, Product: 0x0002}, value)
+	assert.Equal(t, "1d6b:0002", value.String())
+}
+
+func TestParseIDInvalid(t *testing.T) {
+	for _, input := range []string{"", "1d6b", "1d6b:002", "1d6b-0002", "1d6b:0002:0001", "1d6g:0002"} {
+		value, err := ParseID(USB, input)
+		assert.Error(t, err, input)
+		assert.Equal(t, ID{}, value)
+	}
+}
+
+func TestParseModalias(t *testing.T) {
+	value, err := ParseModalias("usb:v1D6Bp0002d0510dc09dsc00dp03ic09isc00ip00in00")
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, ID{Bus: USB, Vendor: 0x1d6b, Product: 0x0002}, value)
+
+	value, err = ParseModalias("pci:v00008086d00001237sv00000000sd00000000bc06sc00i00")
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+	assert.Equal(t, ID{Bus: PCI, Vendor: 0x8086, Product: 0x1237}, value)
+}
+
+func TestParseModaliasInvalid(t *testing.T) {
+	for _, input := range []string{"", "usb:v1D6B", "pci:v00018086d00001237", "platform:serial8250", "acpi:PNP0501:"} {
+		value, err := ParseModalias(input)
+		assert.Error(t, err, input)
+		assert.Equal(t, ID{}, value)
+	}
+}
+
+func TestToUrnDev(t *testing.T) {
+	id := ID{Bus: PCI, Vendor: 0x8086, Product: 0x100e}
+
+	value, err := id.ToUrnDev(" 00-1B-21-AB-CD-EF ")
+	if err != nil {
+		t.Fatalf("Failed to convert")
+		return
+	}
+	assert.Equal(t, "urn:dev:pci:8086-100e:00-1B-21-AB-CD-EF", value.FullName)
+	assert.Equal(t, "pci", value.Subtype)
+	assert.Equal(t, []string{"8086-100e", "00-1B-21-AB-CD-EF"}, value.Identifier)
+}
+
+func TestToUrnDevEncodedSerial(t *testing.T) {
+	id := ID{Bus: USB, Vendor: 0x0403, Product: 0x6001}
+
+	value, err := id.ToUrnDev("A B_C")
+	if err != nil {
+		t.Fatalf("Failed to convert")
+		return
+	}
+	assert.Equal(t, "urn:dev:usb:0403-6001:x:4120425f43", value.FullName)
+	assert.Equal(t, []string{"0403-6001", "x", "4120425f43"}, value.Identifier)
+}
+
+func TestToUrnDevNoSerial(t *testing.T) {
+	id := ID{Bus: USB, Vendor: 0x1d6b, Product: 0x0002}
+
+	value, err := id.ToUrnDev("")
+	if err != nil {
+		t.Fatalf("Failed to convert")
+		return
+	}
+	assert.Equal(t, "urn:dev:usb:1d6b-0002", value.FullName)
+}
+
+func TestToUrnDevInvalidBus(t *testing.T) {
+	_, err := ID{Bus: Bus(7)}.ToUrnDev("1234")
+	assert.Error(t, err)
+}
+
+func TestParseDatabaseUsb(t *testing.T) {
+	db, err := ParseDatabase(strings.NewReader(testUsbIds))
+	if err != nil {
+		t.Fatalf("Failed to parse")
+		return
+	}
+
+	name, ok := db.VendorName(ID{Bus: USB, Vendor: 0x0403, Product: 0x6001})
+	assert.True(t, ok)
+	assert.Equal(t, "Future Technology Devices International, Ltd", name)
+
+	name, ok = db.ProductName(ID{Bus: USB, Vendor: 0x0403, Product: 0x6001})
+	assert.True(t, ok)
+	assert.Equal(t, "FT232 Serial (UART) IC", name)
+
+	name, ok = db.ProductName(ID{Bus: USB, Vendor: 0x1d6b, Product: 0x0003})
+	assert.True(t, ok)
+	assert.Equal(t, "3.0 root hub", name)
+
+	_, ok = db.ProductName(ID{Bus: USB, Vendor: 0x1d6b, Product: 0x0004})
+	assert.False(t, ok)
+
+	_, ok = db.VendorName(ID{Bus: USB, Vendor: 0x0000})
+	assert.False(t, ok)
+
+	// Class section entries must not leak into the last vendor
+	_, ok = db.ProductName(ID{Bus: USB, Vendor: 0x1d6b, Product: 0x0000})
+	assert.False(t, ok)
+}
+
+func TestOpenDatabasePci(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pci.ids")
+	if err := os.WriteFile(path, []byte(testPciIds), 0o600); err != nil {
+		t.Fatalf("Failed to write")
+		return
+	}
+
+	db, err := OpenDatabase(path)
+	if err != nil {
+		t.Fatalf("Failed to open")
+		return
+	}
+
+	name, ok := db.ProductName(ID{Bus: PCI, Vendor: 0x8086, Product: 0x100e})
+	assert.True(t, ok)
+	assert.Equal(t, "82540EM Gigabit Ethernet Controller", name)
+
+	_, err = OpenDatabase(filepath.Join(t.TempDir(), "missing.ids"))
+	assert.Error(t, err)
+}